@@ -0,0 +1,243 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"syscall"
+)
+
+// DefaultSupergroup is the superuser group of HDFS by default, see dfs.permissions.superusergroup.
+const DefaultSupergroup = "supergroup"
+
+// AccessRule is the rule of the permission checking of HDFS by which an access is allowed or denied.
+type AccessRule int
+
+const (
+	AccessRuleSuperuser  AccessRule = iota // the user is the superuser, or a member of the superuser group, never denied
+	AccessRuleOwner                        // the user is the owner, checked against the permission of the owner
+	AccessRuleNamedUser                    // the user is named by an ACL entry, checked against it masked by the mask
+	AccessRuleGroup                        // the user is a member of the group owner, checked against the permission of the group, or its ACL entry masked by the mask
+	AccessRuleNamedGroup                   // the user is a member of groups named by ACL entries, checked against each of them masked by the mask
+	AccessRuleOther                        // none of the above, checked against the permission of the others
+)
+
+func (r AccessRule) String() string {
+	switch r {
+	case AccessRuleSuperuser:
+		return "superuser"
+	case AccessRuleOwner:
+		return "owner"
+	case AccessRuleNamedUser:
+		return "named user"
+	case AccessRuleGroup:
+		return "group"
+	case AccessRuleNamedGroup:
+		return "named group"
+	case AccessRuleOther:
+		return "other"
+	}
+	return fmt.Sprintf("AccessRule(%d)", int(r))
+}
+
+// AccessNode is a file or directory to be checked, with its ACL if any.
+type AccessNode struct {
+	// The status of the file or directory, with PathPrefix and PathSuffix naming its path,
+	// as returned by GETFILESTATUS or LISTSTATUS.
+	Status FileStatusProperties
+	// The entries of the AclStatus of the file or directory, nil if it has no ACL.
+	// With an ACL, the permission of the group of Status is the mask, as the namenode returns.
+	Acl []AclEntry
+}
+
+// AccessChecker checks an access to files and directories offline by their status,
+// as the permission checking of the namenode does, saving a CHECKACCESS per file.
+//
+// The user is allowed if the superuser. Otherwise, the user is checked against the permission of the owner if the owner,
+// or else against the ACL entry naming the user, or else against the ACL entries of all groups of the user, any of which
+// allowing is enough, or else against the permission of the others.
+// The ACL entries except those of the owner and the others are masked by the mask.
+// Without an ACL, the group owner is checked against the permission of the group, the same as its ACL entry.
+type AccessChecker struct {
+	User       string   // The user to check.
+	Groups     []string // The groups the user is a member of, as resolved by the namenode.
+	Superuser  string   // The user running the namenode, who is the superuser, such as hdfs, optional.
+	Supergroup string   // The superuser group, see dfs.permissions.superusergroup, DefaultSupergroup if empty.
+}
+
+// AccessDecision is the decision of an access, explaining the rule by which it is allowed or denied.
+type AccessDecision struct {
+	Allowed  bool       // Whether the access is allowed.
+	User     string     // The user checked.
+	Path     string     // The path decided by, the path checked, or an ancestor denying the traversal.
+	Traverse bool       // Whether Path is an ancestor, the traversal of which is denied.
+	Access   FsAction   // The actions requested on Path, --x to traverse.
+	Rule     AccessRule // The rule applied.
+	Granted  FsAction   // The actions permitted by Rule, masked by the mask if by ACL entries, of all Entries together.
+	Entries  []AclEntry // The ACL entries of Rule, if by ACL entries.
+}
+
+// String returns the explanation of the decision, such as
+// deny rw- on /data/f to alice as named user permitted r-- by user:alice:rwx masked.
+func (d AccessDecision) String() string {
+	verb := "deny"
+	if d.Allowed {
+		verb = "allow"
+	}
+	on := "on " + d.Path
+	if d.Traverse {
+		on = "to traverse " + d.Path
+	}
+	s := fmt.Sprintf("%s %s %s to %s as %s", verb, d.Access, on, d.User, d.Rule)
+	if d.Rule == AccessRuleSuperuser {
+		return s
+	}
+	s += " permitted " + d.Granted.String()
+	if len(d.Entries) > 0 {
+		s += " by " + FormatAclSpec(d.Entries, true) + " masked"
+	}
+	return s
+}
+
+// Err returns nil if the access is allowed, or an error wrapping fs.ErrPermission explaining why it is denied.
+func (d AccessDecision) Err() error {
+	if d.Allowed {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", d, fs.ErrPermission)
+}
+
+// Check checks the access of actions on the last of nodes, after the traversal of all the others,
+// which are the ancestors of the last one in order from the root, and need EXECUTE to be traversed.
+// An error is returned if nodes is empty, or any ancestor is not a directory.
+func (c *AccessChecker) Check(nodes []AccessNode, access FsAction) (AccessDecision, error) {
+	if len(nodes) == 0 {
+		return AccessDecision{}, errors.New("no file to check access")
+	}
+	for i := range nodes[:len(nodes)-1] {
+		if fi := &nodes[i].Status; fi.Type != FileTypeDirectory {
+			return AccessDecision{}, &fs.PathError{Op: "access", Path: path.Join(fi.PathPrefix, fi.PathSuffix), Err: syscall.ENOTDIR}
+		}
+	}
+
+	if c.isSuperuser() {
+		fi := &nodes[len(nodes)-1].Status
+		return AccessDecision{
+			Allowed: true,
+			User:    c.User,
+			Path:    path.Join(fi.PathPrefix, fi.PathSuffix),
+			Access:  access,
+			Rule:    AccessRuleSuperuser,
+			Granted: FsActionAll,
+		}, nil
+	}
+	for i := range nodes[:len(nodes)-1] {
+		if d := c.check(&nodes[i], FsActionExecute); !d.Allowed {
+			d.Traverse = true
+			return d, nil
+		}
+	}
+	return c.check(&nodes[len(nodes)-1], access), nil
+}
+
+// check checks the access of actions on the node, regardless of the superuser.
+func (c *AccessChecker) check(node *AccessNode, access FsAction) AccessDecision {
+	fi := &node.Status
+	d := AccessDecision{User: c.User, Path: path.Join(fi.PathPrefix, fi.PathSuffix), Access: access}
+	decide := func(rule AccessRule, granted FsAction, entries ...AclEntry) AccessDecision {
+		d.Allowed, d.Rule, d.Granted, d.Entries = granted.Implies(access), rule, granted, entries
+		return d
+	}
+
+	if fi.Owner == c.User {
+		return decide(AccessRuleOwner, fi.Permission.User())
+	}
+	if !hasAccessAcl(node.Acl) {
+		if c.isMember(fi.Group) {
+			return decide(AccessRuleGroup, fi.Permission.Group())
+		}
+		return decide(AccessRuleOther, fi.Permission.Other())
+	}
+
+	mask := fi.Permission.Group()
+	for _, e := range node.Acl {
+		if e.Scope == AclEntryScopeAccess && e.Type == AclEntryTypeMask {
+			mask = e.Permission
+		}
+	}
+	for _, e := range node.Acl {
+		if e.Scope == AclEntryScopeAccess && e.Type == AclEntryTypeUser && e.Name != "" && e.Name == c.User {
+			return decide(AccessRuleNamedUser, e.Permission.And(mask), e)
+		}
+	}
+	// any group of the user allowing is enough, otherwise the user is denied by all groups matched
+	var matched []AclEntry
+	var rule AccessRule
+	var granted FsAction
+	for _, e := range node.Acl {
+		if e.Scope != AclEntryScopeAccess || e.Type != AclEntryTypeGroup {
+			continue
+		}
+		group, r := e.Name, AccessRuleNamedGroup
+		if group == "" {
+			group, r = fi.Group, AccessRuleGroup
+		}
+		if !c.isMember(group) {
+			continue
+		}
+		if e.Permission.And(mask).Implies(access) {
+			return decide(r, e.Permission.And(mask), e)
+		}
+		if len(matched) == 0 {
+			rule = r
+		}
+		matched = append(matched, e)
+		granted = granted.Or(e.Permission.And(mask))
+	}
+	if len(matched) > 0 {
+		// denied even if the groups together permit the access
+		d = decide(rule, granted, matched...)
+		d.Allowed = false
+		return d
+	}
+	return decide(AccessRuleOther, fi.Permission.Other())
+}
+
+// isSuperuser reports whether the user is the superuser, or a member of the superuser group.
+func (c *AccessChecker) isSuperuser() bool {
+	if c.Superuser != "" && c.User == c.Superuser {
+		return true
+	}
+	supergroup := c.Supergroup
+	if supergroup == "" {
+		supergroup = DefaultSupergroup
+	}
+	return c.isMember(supergroup)
+}
+
+// isMember reports whether the user is a member of group.
+func (c *AccessChecker) isMember(group string) bool {
+	for _, g := range c.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAccessAcl reports whether entries have any access ACL entry, even an unnamed one alone,
+// as FSPermissionChecker of HDFS does, otherwise the permission alone is checked,
+// as a directory may have a default ACL only.
+func hasAccessAcl(entries []AclEntry) bool {
+	for _, e := range entries {
+		if e.Scope == AclEntryScopeAccess {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs_test
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/searKing/webhdfs"
+)
+
+// accessNode returns the node of name, with the ACL entries of spec if any.
+func accessNode(t *testing.T, name string, typ webhdfs.FileType, owner, group string, perm webhdfs.Permission, spec string) webhdfs.AccessNode {
+	dir, file := path.Split(name)
+	n := webhdfs.AccessNode{Status: webhdfs.FileStatusProperties{
+		PathPrefix: dir,
+		PathSuffix: file,
+		Type:       typ,
+		Owner:      owner,
+		Group:      group,
+		Permission: perm,
+	}}
+	if spec != "" {
+		acl, err := webhdfs.ParseAclSpec(spec, true)
+		if err != nil {
+			t.Fatalf("webhdfs ParseAclSpec failed: %s", err)
+		}
+		n.Acl = acl
+	}
+	return n
+}
+
+func TestAccessChecker_Check(t *testing.T) {
+	root := accessNode(t, "/", webhdfs.FileTypeDirectory, "hdfs", "supergroup", 0755, "")
+	data := accessNode(t, "/data", webhdfs.FileTypeDirectory, "hdfs", "supergroup", 0755, "")
+	tests := []struct {
+		name    string
+		checker webhdfs.AccessChecker
+		nodes   []webhdfs.AccessNode
+		access  webhdfs.FsAction
+		want    webhdfs.AccessDecision
+		str     string
+	}{
+		{
+			name:    "owner",
+			checker: webhdfs.AccessChecker{User: "alice", Groups: []string{"staff"}},
+			nodes:   []webhdfs.AccessNode{root, data, accessNode(t, "/data/f", webhdfs.FileTypeFile, "alice", "staff", 0400, "")},
+			access:  webhdfs.FsActionWrite,
+			want: webhdfs.AccessDecision{Allowed: false, User: "alice", Path: "/data/f", Access: webhdfs.FsActionWrite,
+				Rule: webhdfs.AccessRuleOwner, Granted: webhdfs.FsActionRead},
+			str: "deny -w- on /data/f to alice as owner permitted r--",
+		},
+		{
+			// the mask is the group bits r--
+			name:    "named user masked",
+			checker: webhdfs.AccessChecker{User: "bob"},
+			nodes:   []webhdfs.AccessNode{root, data, accessNode(t, "/data/f", webhdfs.FileTypeFile, "alice", "staff", 0640, "user:bob:rw-,group::r--")},
+			access:  webhdfs.FsActionWrite,
+			want: webhdfs.AccessDecision{Allowed: false, User: "bob", Path: "/data/f", Access: webhdfs.FsActionWrite,
+				Rule: webhdfs.AccessRuleNamedUser, Granted: webhdfs.FsActionRead,
+				Entries: []webhdfs.AclEntry{{Type: webhdfs.AclEntryTypeUser, Name: "bob", Permission: webhdfs.FsActionReadWrite}}},
+			str: "deny -w- on /data/f to bob as named user permitted r-- by user:bob:rw- masked",
+		},
+		{
+			name:    "named user masked by mask entry",
+			checker: webhdfs.AccessChecker{User: "bob"},
+			nodes:   []webhdfs.AccessNode{root, data, accessNode(t, "/data/f", webhdfs.FileTypeFile, "alice", "staff", 0640, "user:bob:rw-,group::r--,mask::rw-")},
+			access:  webhdfs.FsActionReadWrite,
+			want: webhdfs.AccessDecision{Allowed: true, User: "bob", Path: "/data/f", Access: webhdfs.FsActionReadWrite,
+				Rule: webhdfs.AccessRuleNamedUser, Granted: webhdfs.FsActionReadWrite,
+				Entries: []webhdfs.AclEntry{{Type: webhdfs.AclEntryTypeUser, Name: "bob", Permission: webhdfs.FsActionReadWrite}}},
+			str: "allow rw- on /data/f to bob as named user permitted rw- by user:bob:rw- masked",
+		},
+		{
+			// with an ACL, the group bits rwx are the mask, and the group owner is checked against its entry
+			name:    "unnamed group entry",
+			checker: webhdfs.AccessChecker{User: "carol", Groups: []string{"staff"}},
+			nodes:   []webhdfs.AccessNode{root, data, accessNode(t, "/data/f", webhdfs.FileTypeFile, "alice", "staff", 0770, "user:bob:rwx,group::r--")},
+			access:  webhdfs.FsActionWrite,
+			want: webhdfs.AccessDecision{Allowed: false, User: "carol", Path: "/data/f", Access: webhdfs.FsActionWrite,
+				Rule: webhdfs.AccessRuleGroup, Granted: webhdfs.FsActionRead,
+				Entries: []webhdfs.AclEntry{{Type: webhdfs.AclEntryTypeGroup, Permission: webhdfs.FsActionRead}}},
+			str: "deny -w- on /data/f to carol as group permitted r-- by group::r-- masked",
+		},
+		{
+			// an unnamed group entry alone is an ACL too, checked against the group bits rwx as the mask
+			name:    "unnamed group entry alone",
+			checker: webhdfs.AccessChecker{User: "carol", Groups: []string{"staff"}},
+			nodes:   []webhdfs.AccessNode{root, data, accessNode(t, "/data/f", webhdfs.FileTypeFile, "alice", "staff", 0770, "group::r--")},
+			access:  webhdfs.FsActionWrite,
+			want: webhdfs.AccessDecision{Allowed: false, User: "carol", Path: "/data/f", Access: webhdfs.FsActionWrite,
+				Rule: webhdfs.AccessRuleGroup, Granted: webhdfs.FsActionRead,
+				Entries: []webhdfs.AclEntry{{Type: webhdfs.AclEntryTypeGroup, Permission: webhdfs.FsActionRead}}},
+			str: "deny -w- on /data/f to carol as group permitted r-- by group::r-- masked",
+		},
+		{
+			// without an ACL, the group owner is checked against the group bits
+			name:    "group bits",
+			checker: webhdfs.AccessChecker{User: "carol", Groups: []string{"staff"}},
+			nodes:   []webhdfs.AccessNode{root, data, accessNode(t, "/data/f", webhdfs.FileTypeFile, "alice", "staff", 0770, "")},
+			access:  webhdfs.FsActionWrite,
+			want: webhdfs.AccessDecision{Allowed: true, User: "carol", Path: "/data/f", Access: webhdfs.FsActionWrite,
+				Rule: webhdfs.AccessRuleGroup, Granted: webhdfs.FsActionAll},
+			str: "allow -w- on /data/f to carol as group permitted rwx",
+		},
+		{
+			name:    "named groups any allowing",
+			checker: webhdfs.AccessChecker{User: "dave", Groups: []string{"g1", "g2"}},
+			nodes:   []webhdfs.AccessNode{root, data, accessNode(t, "/data/g", webhdfs.FileTypeFile, "alice", "staff", 0660, "group::---,group:g1:r--,group:g2:-w-")},
+			access:  webhdfs.FsActionWrite,
+			want: webhdfs.AccessDecision{Allowed: true, User: "dave", Path: "/data/g", Access: webhdfs.FsActionWrite,
+				Rule: webhdfs.AccessRuleNamedGroup, Granted: webhdfs.FsActionWrite,
+				Entries: []webhdfs.AclEntry{{Type: webhdfs.AclEntryTypeGroup, Name: "g2", Permission: webhdfs.FsActionWrite}}},
+			str: "allow -w- on /data/g to dave as named group permitted -w- by group:g2:-w- masked",
+		},
+		{
+			// no single group allows rw-, though all together do
+			name:    "named groups all denying",
+			checker: webhdfs.AccessChecker{User: "dave", Groups: []string{"g1", "g2"}},
+			nodes:   []webhdfs.AccessNode{root, data, accessNode(t, "/data/g", webhdfs.FileTypeFile, "alice", "staff", 0660, "group::---,group:g1:r--,group:g2:-w-")},
+			access:  webhdfs.FsActionReadWrite,
+			want: webhdfs.AccessDecision{Allowed: false, User: "dave", Path: "/data/g", Access: webhdfs.FsActionReadWrite,
+				Rule: webhdfs.AccessRuleNamedGroup, Granted: webhdfs.FsActionReadWrite,
+				Entries: []webhdfs.AclEntry{
+					{Type: webhdfs.AclEntryTypeGroup, Name: "g1", Permission: webhdfs.FsActionRead},
+					{Type: webhdfs.AclEntryTypeGroup, Name: "g2", Permission: webhdfs.FsActionWrite},
+				}},
+			str: "deny rw- on /data/g to dave as named group permitted rw- by group:g1:r--,group:g2:-w- masked",
+		},
+		{
+			name:    "fall through to other",
+			checker: webhdfs.AccessChecker{User: "eve", Groups: []string{"guests"}},
+			nodes:   []webhdfs.AccessNode{root, data, accessNode(t, "/data/f", webhdfs.FileTypeFile, "alice", "staff", 0774, "user:bob:rwx,group::rwx,group:g1:rwx")},
+			access:  webhdfs.FsActionRead,
+			want: webhdfs.AccessDecision{Allowed: true, User: "eve", Path: "/data/f", Access: webhdfs.FsActionRead,
+				Rule: webhdfs.AccessRuleOther, Granted: webhdfs.FsActionRead},
+			str: "allow r-- on /data/f to eve as other permitted r--",
+		},
+		{
+			name:    "superuser",
+			checker: webhdfs.AccessChecker{User: "hdfs", Superuser: "hdfs"},
+			nodes: []webhdfs.AccessNode{root, accessNode(t, "/private", webhdfs.FileTypeDirectory, "alice", "staff", 0700, ""),
+				accessNode(t, "/private/f", webhdfs.FileTypeFile, "alice", "staff", 0000, "")},
+			access: webhdfs.FsActionAll,
+			want: webhdfs.AccessDecision{Allowed: true, User: "hdfs", Path: "/private/f", Access: webhdfs.FsActionAll,
+				Rule: webhdfs.AccessRuleSuperuser, Granted: webhdfs.FsActionAll},
+			str: "allow rwx on /private/f to hdfs as superuser",
+		},
+		{
+			name:    "supergroup",
+			checker: webhdfs.AccessChecker{User: "frank", Groups: []string{"users", webhdfs.DefaultSupergroup}},
+			nodes:   []webhdfs.AccessNode{root, accessNode(t, "/f", webhdfs.FileTypeFile, "alice", "staff", 0000, "")},
+			access:  webhdfs.FsActionWrite,
+			want: webhdfs.AccessDecision{Allowed: true, User: "frank", Path: "/f", Access: webhdfs.FsActionWrite,
+				Rule: webhdfs.AccessRuleSuperuser, Granted: webhdfs.FsActionAll},
+			str: "allow -w- on /f to frank as superuser",
+		},
+		{
+			name:    "not supergroup",
+			checker: webhdfs.AccessChecker{User: "frank", Groups: []string{webhdfs.DefaultSupergroup}, Supergroup: "admins"},
+			nodes:   []webhdfs.AccessNode{root, accessNode(t, "/f", webhdfs.FileTypeFile, "alice", "staff", 0000, "")},
+			access:  webhdfs.FsActionWrite,
+			want: webhdfs.AccessDecision{Allowed: false, User: "frank", Path: "/f", Access: webhdfs.FsActionWrite,
+				Rule: webhdfs.AccessRuleOther, Granted: webhdfs.FsActionNone},
+			str: "deny -w- on /f to frank as other permitted ---",
+		},
+		{
+			name:    "traversal denied",
+			checker: webhdfs.AccessChecker{User: "bob", Groups: []string{"users"}},
+			nodes: []webhdfs.AccessNode{root, accessNode(t, "/private", webhdfs.FileTypeDirectory, "alice", "staff", 0744, ""),
+				accessNode(t, "/private/f", webhdfs.FileTypeFile, "alice", "staff", 0666, "")},
+			access: webhdfs.FsActionRead,
+			want: webhdfs.AccessDecision{Allowed: false, User: "bob", Path: "/private", Traverse: true, Access: webhdfs.FsActionExecute,
+				Rule: webhdfs.AccessRuleOther, Granted: webhdfs.FsActionRead},
+			str: "deny --x to traverse /private to bob as other permitted r--",
+		},
+		{
+			// a default ACL applies to the children created, not to the directory itself
+			name:    "default acl only",
+			checker: webhdfs.AccessChecker{User: "bob", Groups: []string{"users"}},
+			nodes: []webhdfs.AccessNode{root, accessNode(t, "/shared", webhdfs.FileTypeDirectory, "alice", "staff", 0750,
+				"default:user::rwx,default:user:bob:rwx,default:group::r-x,default:mask::rwx,default:other::---")},
+			access: webhdfs.FsActionReadExecute,
+			want: webhdfs.AccessDecision{Allowed: false, User: "bob", Path: "/shared", Access: webhdfs.FsActionReadExecute,
+				Rule: webhdfs.AccessRuleOther, Granted: webhdfs.FsActionNone},
+			str: "deny r-x on /shared to bob as other permitted ---",
+		},
+	}
+	for _, tt := range tests {
+		got, err := tt.checker.Check(tt.nodes, tt.access)
+		if err != nil {
+			t.Errorf("%s: webhdfs Check failed: %s", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Check: got %+v, want %+v", tt.name, got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("%s: String(): got %q, want %q", tt.name, got.String(), tt.str)
+		}
+		if err := got.Err(); (err == nil) != tt.want.Allowed || (err != nil && !errors.Is(err, fs.ErrPermission)) {
+			t.Errorf("%s: Err(): got %v", tt.name, err)
+		}
+	}
+}
+
+func TestAccessChecker_Check_Invalid(t *testing.T) {
+	c := webhdfs.AccessChecker{User: "hdfs", Superuser: "hdfs"}
+	if _, err := c.Check(nil, webhdfs.FsActionRead); err == nil {
+		t.Errorf("Check of no nodes: got nil, want error")
+	}
+
+	nodes := []webhdfs.AccessNode{
+		accessNode(t, "/", webhdfs.FileTypeDirectory, "hdfs", "supergroup", 0755, ""),
+		accessNode(t, "/f", webhdfs.FileTypeFile, "alice", "staff", 0644, ""),
+		accessNode(t, "/f/g", webhdfs.FileTypeFile, "alice", "staff", 0644, ""),
+	}
+	_, err := c.Check(nodes, webhdfs.FsActionRead)
+	var pathErr *fs.PathError
+	if !errors.Is(err, syscall.ENOTDIR) || !errors.As(err, &pathErr) || pathErr.Path != "/f" {
+		t.Errorf("Check of a file ancestor: got %v, want %v of /f", err, syscall.ENOTDIR)
+	}
+}
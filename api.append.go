@@ -65,6 +65,14 @@ type AppendRequest struct {
 	// Valid Values		true|false
 	// Syntax			Any Bool.
 	NoDirect *bool
+	// Name				novariablelength
+	// Description		If true, the data is not appended to a new block, so that the file is not left
+	//					with blocks of variable length; if false, the namenode decides as it does by default.
+	// Type				boolean
+	// Default Value	false
+	// Valid Values		true, false
+	// Syntax			true
+	NoVariableLength *bool
 }
 
 type AppendResponse struct {
@@ -98,6 +106,9 @@ func (req *AppendRequest) RawQuery() string {
 	if req.NoDirect != nil {
 		v.Set("noredirect", fmt.Sprintf("%t", types.Value(req.NoDirect)))
 	}
+	if req.NoVariableLength != nil {
+		v.Set("novariablelength", fmt.Sprintf("%t", types.Value(req.NoVariableLength)))
+	}
 	return v.Encode()
 }
 
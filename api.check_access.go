@@ -38,7 +38,7 @@ type CheckAccessRequest struct {
 	// Valid Values		Strings matching regex pattern  "[r-][w-][x-] "
 	// Syntax		 	"[r-][w-][x-] "
 	// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Fs_Action
-	Fsaction *string `validate:"required"`
+	Fsaction *FsAction `validate:"required"`
 }
 
 type CheckAccessResponse struct {
@@ -63,7 +63,7 @@ func (req *CheckAccessRequest) RawQuery() string {
 		v.Set("doas", types.Value(req.ProxyUser.DoAs))
 	}
 	if req.Fsaction != nil {
-		v.Set("fsaction", types.Value(req.Fsaction))
+		v.Set("fsaction", types.Value(req.Fsaction).String())
 	}
 	return v.Encode()
 }
@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/searKing/golang/go/exp/types"
 
@@ -37,6 +38,10 @@ type ConcatRequest struct {
 	// Valid Values		A list of comma seperated absolute FileSystem paths without scheme and authority.
 	// Syntax			Any string.
 	Sources *string
+
+	// SourcePaths is a typed list of source paths, joined by comma and sent as sources,
+	// after Sources if both are set.
+	SourcePaths []string
 }
 
 type ConcatResponse struct {
@@ -61,8 +66,13 @@ func (req *ConcatRequest) RawQuery() string {
 		v.Set("doas", types.Value(req.ProxyUser.DoAs))
 	}
 
+	var sources []string
 	if req.Sources != nil {
-		v.Set("sources", types.Value(req.Sources))
+		sources = append(sources, types.Value(req.Sources))
+	}
+	sources = append(sources, req.SourcePaths...)
+	if len(sources) > 0 {
+		v.Set("sources", strings.Join(sources, ","))
 	}
 	return v.Encode()
 }
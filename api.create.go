@@ -94,6 +94,46 @@ type CreateRequest struct {
 	// Valid Values		true|false
 	// Syntax			Any Bool.
 	NoDirect *bool
+	// Name				createparent
+	// Description		If the parent directories do not exist, should they be created?
+	// Type				boolean
+	// Default Value	true
+	// Valid Values		true, false
+	// Syntax			true
+	CreateParent *bool
+	// Name				unmaskedpermission
+	// Description		The permission of a file/directory, not masked by the umask,
+	//					applied if the parent directory has a default ACL.
+	// Type				Octal
+	// Default Value	null
+	// Valid Values		0 - 1777
+	// Syntax			Any radix-8 integer (leading zeros may be omitted.)
+	UnmaskedPermission *int
+	// Name				ecpolicy, Erasure Coding Policy
+	// Description		The name of the erasure coding policy the file is created with,
+	//					instead of the one inherited from the parent directory.
+	// Type				String
+	// Default Value	<empty>
+	// Valid Values		Any valid erasure coding policy name;
+	// Syntax			Any string.
+	ECPolicy *string
+	// Name				storagepolicy
+	// Description		The name of the storage policy the file is created with.
+	// Type				String
+	// Default Value	<empty>
+	// Valid Values		Any valid storage policy name; see GETALLSTORAGEPOLICY.
+	// Syntax			Any string.
+	StoragePolicy *string
+	// Name				createflag
+	// Description		Enum of possible flags to process while creating a file
+	// Type				enumerated strings
+	// Default Value	<empty>
+	// Valid Values		Legal combinations of create, overwrite, append and sync_block
+	// Syntax			See note below
+	// Note that the following combinations are not valid:
+	// - append,create
+	// - create,append,overwrite
+	CreateFlag []CreateFlag
 }
 
 type CreateResponse struct {
@@ -139,6 +179,21 @@ func (req *CreateRequest) RawQuery() string {
 	if req.NoDirect != nil {
 		v.Set("noredirect", fmt.Sprintf("%t", types.Value(req.NoDirect)))
 	}
+	if req.CreateParent != nil {
+		v.Set("createparent", fmt.Sprintf("%t", types.Value(req.CreateParent)))
+	}
+	if req.UnmaskedPermission != nil {
+		v.Set("unmaskedpermission", fmt.Sprintf("%#o", types.Value(req.UnmaskedPermission)))
+	}
+	if req.ECPolicy != nil {
+		v.Set("ecpolicy", types.Value(req.ECPolicy))
+	}
+	if req.StoragePolicy != nil {
+		v.Set("storagepolicy", types.Value(req.StoragePolicy))
+	}
+	if len(req.CreateFlag) > 0 {
+		v.Set("createflag", FormatCreateFlags(req.CreateFlag))
+	}
 	return v.Encode()
 }
 
@@ -72,12 +72,7 @@ func (req *DeleteRequest) RawQuery() string {
 
 func (resp *DeleteResponse) UnmarshalHTTP(httpResp *http.Response) error {
 	resp.HttpResponse.UnmarshalHTTP(httpResp)
-
-	if isSuccessHttpCode(httpResp.StatusCode) {
-		return nil
-	}
 	defer resp.Body.Close()
-	// direct or error
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
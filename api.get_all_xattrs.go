@@ -105,7 +105,7 @@ func (c *Client) getAllXAttrs(ctx context.Context, req *GetAllXAttrsRequest) (*G
 	if err != nil {
 		return nil, err
 	}
-	if req.Encoding != nil {
+	if req.Encoding != nil && !req.Encoding.known() {
 		return nil, fmt.Errorf("unknown param %s : %s", HttpQueryParamKeyXAttrValueEncoding, types.Value((*string)(req.Encoding)))
 	}
 
@@ -29,6 +29,21 @@ type GetFileBlockLocationsRequest struct {
 	//
 	// Path is a required field
 	Path *string `validate:"required"`
+
+	// Name				offset
+	// Description		The starting byte position.
+	// Type				long
+	// Default Value	0
+	// Valid Values		>= 0
+	// Syntax			Any integer.
+	Offset *int64
+	// Name				length
+	// Description		The number of bytes to be processed.
+	// Type				long
+	// Default Value	null (means the entire file)
+	// Valid Values		>= 0 or null
+	// Syntax			Any integer.
+	Length *int64
 }
 
 type GetFileBlockLocationsResponse struct {
@@ -54,6 +69,12 @@ func (req *GetFileBlockLocationsRequest) RawQuery() string {
 		v.Set("doas", types.Value(req.ProxyUser.DoAs))
 	}
 
+	if req.Offset != nil {
+		v.Set("offset", fmt.Sprintf("%d", types.Value(req.Offset)))
+	}
+	if req.Length != nil {
+		v.Set("length", fmt.Sprintf("%d", types.Value(req.Length)))
+	}
 	return v.Encode()
 }
 
@@ -0,0 +1,182 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/searKing/golang/go/errors"
+	"github.com/searKing/golang/go/exp/types"
+)
+
+type GetSnapshotDiffListingRequest struct {
+	Authentication
+	ProxyUser
+	CSRF
+	HttpRequest
+
+	// Path of the object to get.
+	//
+	// Path is a required field
+	Path *string `validate:"required"`
+
+	// Name				oldsnapshotname
+	// Description		The old name of the snapshot to be renamed.
+	// Type				String
+	// Default Value	null
+	// Valid Values		An existing snapshot name.
+	// Syntax			Any string.
+	// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Old_Snapshot_Name
+	Oldsnapshotname *string `json:"oldsnapshotname,omitempty" validate:"required"`
+	// Name				snapshotname
+	// Description		The name of the snapshot to be created/deleted. Or the new name for snapshot rename.
+	// Type				String
+	// Default Value	null
+	// Valid Values		Any valid snapshot name.
+	// Syntax			Any string.
+	// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Snapshot_Name
+	Snapshotname *string `json:"snapshotname,omitempty" validate:"required"`
+	// Name				snapshotdiffstartpath
+	// Description		The path to start listing from, LastPath of the previous page.
+	// Type				String
+	// Default Value	<empty>
+	// Valid Values		LastPath of SnapshotDiffReportListing.
+	// Syntax			Any string.
+	Snapshotdiffstartpath *string `json:"snapshotdiffstartpath,omitempty"`
+	// Name				snapshotdiffindex
+	// Description		The index to start listing from, LastIndex of the previous page.
+	// Type				int
+	// Default Value	-1
+	// Valid Values		LastIndex of SnapshotDiffReportListing.
+	// Syntax			Any integer.
+	Snapshotdiffindex *int64 `json:"snapshotdiffindex,omitempty"`
+}
+
+type GetSnapshotDiffListingResponse struct {
+	NameNode string `json:"-"`
+	ErrorResponse
+	HttpResponse              `json:"-"`
+	SnapshotDiffReportListing SnapshotDiffReportListing `json:"SnapshotDiffReportListing"`
+}
+
+func (req *GetSnapshotDiffListingRequest) RawPath() string {
+	return types.Value(req.Path)
+}
+func (req *GetSnapshotDiffListingRequest) RawQuery() string {
+	v := url.Values{}
+	v.Set("op", OpGetSnapshotDiffListing)
+	if req.Authentication.Delegation != nil {
+		v.Set("delegation", types.Value(req.Authentication.Delegation))
+	}
+	if req.ProxyUser.Username != nil {
+		v.Set("user.name", types.Value(req.ProxyUser.Username))
+	}
+	if req.ProxyUser.DoAs != nil {
+		v.Set("doas", types.Value(req.ProxyUser.DoAs))
+	}
+
+	if req.Oldsnapshotname != nil {
+		v.Set("oldsnapshotname", types.Value(req.Oldsnapshotname))
+	}
+	if req.Snapshotname != nil {
+		v.Set("snapshotname", types.Value(req.Snapshotname))
+	}
+	if req.Snapshotdiffstartpath != nil {
+		v.Set("snapshotdiffstartpath", types.Value(req.Snapshotdiffstartpath))
+	}
+	if req.Snapshotdiffindex != nil {
+		v.Set("snapshotdiffindex", fmt.Sprintf("%d", types.Value(req.Snapshotdiffindex)))
+	}
+	return v.Encode()
+}
+
+func (resp *GetSnapshotDiffListingResponse) UnmarshalHTTP(httpResp *http.Response) error {
+	resp.HttpResponse.UnmarshalHTTP(httpResp)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return ErrorFromHttpResponse(httpResp)
+	}
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	if err := resp.Exception(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get Snapshot Diff Listing
+// The diff is listed page by page, see GetSnapshotDiffListingPaginator.
+// See also: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Get_Snapshot_Diff
+func (c *Client) GetSnapshotDiffListing(req *GetSnapshotDiffListingRequest) (*GetSnapshotDiffListingResponse, error) {
+	return c.getSnapshotDiffListing(nil, req)
+}
+func (c *Client) GetSnapshotDiffListingWithContext(ctx context.Context, req *GetSnapshotDiffListingRequest) (*GetSnapshotDiffListingResponse, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	return c.getSnapshotDiffListing(ctx, req)
+}
+func (c *Client) getSnapshotDiffListing(ctx context.Context, req *GetSnapshotDiffListingRequest) (*GetSnapshotDiffListingResponse, error) {
+	err := c.opts.Validator.Struct(req)
+	if err != nil {
+		return nil, err
+	}
+
+	nameNodes := c.opts.Addresses
+	if nameNodes == nil {
+		return nil, fmt.Errorf("missing namenode addresses")
+	}
+	var u = c.HttpUrl(req)
+
+	var errs []error
+	for _, addr := range nameNodes {
+		u.Host = addr
+		httpReq, err := http.NewRequest(http.MethodGet, u.String(), nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		httpReq.Close = req.HttpRequest.Close
+		if req.CSRF.XXsrfHeader != nil {
+			httpReq.Header.Set("X-XSRF-HEADER", types.Value(req.CSRF.XXsrfHeader))
+		}
+		if ctx != nil {
+			httpReq = httpReq.WithContext(ctx)
+		}
+		if req.HttpRequest.PreSendHandler != nil {
+			httpReq, err = req.HttpRequest.PreSendHandler(httpReq)
+			if err != nil {
+				return nil, fmt.Errorf("pre send handled: %w", err)
+			}
+		}
+
+		httpResp, err := c.httpClient().Do(httpReq)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var resp GetSnapshotDiffListingResponse
+		resp.NameNode = addr
+
+		if err := resp.UnmarshalHTTP(httpResp); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return &resp, nil
+	}
+	return nil, errors.Multi(errs...)
+}
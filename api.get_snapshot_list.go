@@ -0,0 +1,143 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/searKing/golang/go/exp/types"
+
+	strings_ "github.com/searKing/golang/go/strings"
+
+	"github.com/searKing/golang/go/errors"
+)
+
+type GetSnapshotListRequest struct {
+	Authentication
+	ProxyUser
+	CSRF
+	HttpRequest
+
+	// Path of the object to get.
+	//
+	// Path is a required field
+	Path *string `validate:"required"`
+}
+
+type GetSnapshotListResponse struct {
+	NameNode string `json:"-"`
+	ErrorResponse
+	HttpResponse `json:"-"`
+	SnapshotList SnapshotList `json:"SnapshotList"` // An array of SnapshotStatus
+}
+
+func (req *GetSnapshotListRequest) RawPath() string {
+	return types.Value(req.Path)
+}
+func (req *GetSnapshotListRequest) RawQuery() string {
+	v := url.Values{}
+	v.Set("op", OpGetSnapshotList)
+	if req.Authentication.Delegation != nil {
+		v.Set("delegation", types.Value(req.Authentication.Delegation))
+	}
+	if req.ProxyUser.Username != nil {
+		v.Set("user.name", types.Value(req.ProxyUser.Username))
+	}
+	if req.ProxyUser.DoAs != nil {
+		v.Set("doas", types.Value(req.ProxyUser.DoAs))
+	}
+
+	return v.Encode()
+}
+
+func (resp *GetSnapshotListResponse) UnmarshalHTTP(httpResp *http.Response) error {
+	resp.HttpResponse.UnmarshalHTTP(httpResp)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return ErrorFromHttpResponse(httpResp)
+	}
+	err = json.Unmarshal(body, &resp)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", strings_.Truncate(string(body), MaxHTTPBodyLengthDumped), err)
+	}
+
+	if err := resp.Exception(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get Snapshot List
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Get_Snapshot_List
+func (c *Client) GetSnapshotList(req *GetSnapshotListRequest) (*GetSnapshotListResponse, error) {
+	return c.getSnapshotList(nil, req)
+}
+func (c *Client) GetSnapshotListWithContext(ctx context.Context, req *GetSnapshotListRequest) (*GetSnapshotListResponse, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	return c.getSnapshotList(ctx, req)
+}
+func (c *Client) getSnapshotList(ctx context.Context, req *GetSnapshotListRequest) (*GetSnapshotListResponse, error) {
+	err := c.opts.Validator.Struct(req)
+	if err != nil {
+		return nil, err
+	}
+
+	nameNodes := c.opts.Addresses
+	if nameNodes == nil {
+		return nil, fmt.Errorf("missing namenode addresses")
+	}
+	var u = c.HttpUrl(req)
+
+	var errs []error
+	for _, addr := range nameNodes {
+		u.Host = addr
+		httpReq, err := http.NewRequest(http.MethodGet, u.String(), nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		httpReq.Close = req.HttpRequest.Close
+		if req.CSRF.XXsrfHeader != nil {
+			httpReq.Header.Set("X-XSRF-HEADER", types.Value(req.CSRF.XXsrfHeader))
+		}
+		if ctx != nil {
+			httpReq = httpReq.WithContext(ctx)
+		}
+		if req.HttpRequest.PreSendHandler != nil {
+			httpReq, err = req.HttpRequest.PreSendHandler(httpReq)
+			if err != nil {
+				return nil, fmt.Errorf("pre send handled: %w", err)
+			}
+		}
+
+		httpResp, err := c.httpClient().Do(httpReq)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var resp GetSnapshotListResponse
+		resp.NameNode = addr
+
+		if err := resp.UnmarshalHTTP(httpResp); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return &resp, nil
+	}
+	return nil, errors.Multi(errs...)
+}
@@ -117,7 +117,7 @@ func (c *Client) getXAttr(ctx context.Context, req *GetXAttrRequest) (*GetXAttrR
 	if err != nil {
 		return nil, err
 	}
-	if req.Encoding != nil {
+	if req.Encoding != nil && !req.Encoding.known() {
 		return nil, fmt.Errorf("unknown param %s : %s", HttpQueryParamKeyXAttrValueEncoding, types.Value((*string)(req.Encoding)))
 	}
 
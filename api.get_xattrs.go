@@ -110,7 +110,7 @@ func (c *Client) getXAttrs(ctx context.Context, req *GetXAttrsRequest) (*GetXAtt
 	if err != nil {
 		return nil, err
 	}
-	if req.Encoding != nil {
+	if req.Encoding != nil && !req.Encoding.known() {
 		return nil, fmt.Errorf("unknown param %s : %s", HttpQueryParamKeyXAttrValueEncoding, types.Value((*string)(req.Encoding)))
 	}
 
@@ -30,6 +30,13 @@ const (
 	OpGetSnapshottableDirectoryList = "GETSNAPSHOTTABLEDIRECTORYLIST"
 	OpGetFileBlockLocations         = "GETFILEBLOCKLOCATIONS"
 	OpGetECPolicy                   = "GETECPOLICY"
+	OpGetAclStatus                  = "GETACLSTATUS"
+	OpGetServerDefaults             = "GETSERVERDEFAULTS"
+	OpGetStatus                     = "GETSTATUS"
+	OpGetLinkTarget                 = "GETLINKTARGET"
+	OpGetFileLinkStatus             = "GETFILELINKSTATUS"
+	OpGetSnapshotList               = "GETSNAPSHOTLIST"
+	OpGetSnapshotDiffListing        = "GETSNAPSHOTDIFFLISTING"
 	OpCreate                        = "CREATE"
 	OpMkdirs                        = "MKDIRS"
 	OpCreateSymlink                 = "CREATESYMLINK"
@@ -46,6 +53,11 @@ const (
 	OpRenameSnapshot                = "RENAMESNAPSHOT"
 	OpSetXAttr                      = "SETXATTR"
 	OpRemoveXAttr                   = "REMOVEXATTR"
+	OpModifyAclEntries              = "MODIFYACLENTRIES"
+	OpRemoveAclEntries              = "REMOVEACLENTRIES"
+	OpRemoveDefaultAcl              = "REMOVEDEFAULTACL"
+	OpRemoveAcl                     = "REMOVEACL"
+	OpSetAcl                        = "SETACL"
 	OpSetStoragePolicy              = "SETSTORAGEPOLICY"
 	OpSatisfyStoragePolicy          = "SATISFYSTORAGEPOLICY"
 	OpEnableECPolicy                = "ENABLEECPOLICY"
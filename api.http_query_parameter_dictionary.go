@@ -1,5 +1,10 @@
 package webhdfs
 
+import (
+	"fmt"
+	"strings"
+)
+
 // HTTP Query Parameter Dictionary
 const (
 	HttpQueryParamKeyXAttrName          = "xattr.name"
@@ -8,9 +13,229 @@ const (
 
 // ACL Spec
 // The ACL spec included in ACL modification operations.
-// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#XAttr_value_encoding
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#ACL_Spec
 // See also: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/HdfsPermissionsGuide.html#ACLs_.28Access_Control_Lists.29
 
+// AclEntryScope is the scope of an ACL entry.
+type AclEntryScope int
+
+const (
+	AclEntryScopeAccess  AclEntryScope = iota // access, the entry is enforced on the file or directory
+	AclEntryScopeDefault                      // default, the entry is inherited by the children created in the directory
+)
+
+// AclEntryType is the type of an ACL entry.
+type AclEntryType string
+
+const (
+	AclEntryTypeUser  AclEntryType = "user"  // an entry of the owner, or a named user
+	AclEntryTypeGroup AclEntryType = "group" // an entry of the group owner, or a named group
+	AclEntryTypeMask  AclEntryType = "mask"  // the upper bound of the permissions of named users, named groups and the group owner
+	AclEntryTypeOther AclEntryType = "other" // an entry of all the others
+)
+
+// AclEntry is an entry of an ACL, as AclEntry of Hadoop, formatted in the aclspec syntax as
+// [default:]<type>:[<name>]:[<permission>], such as user:alice:rw- or default:group::r-x.
+// An entry of the owner, the group owner, the mask or the others has an empty Name.
+type AclEntry struct {
+	Scope      AclEntryScope
+	Type       AclEntryType
+	Name       string
+	Permission FsAction
+}
+
+// ParseAclEntry parses an entry in the aclspec syntax, the permission is required if includePermission,
+// and not allowed otherwise, as entries of REMOVEACLENTRIES.
+// A mask or other entry must not have a name, as AclEntry.parseAclEntry of Hadoop.
+func ParseAclEntry(s string, includePermission bool) (AclEntry, error) {
+	var e AclEntry
+	fields := strings.Split(s, ":")
+	i := 0
+	if fields[0] == "default" {
+		e.Scope = AclEntryScopeDefault
+		i++
+	}
+	if i >= len(fields) {
+		return AclEntry{}, fmt.Errorf("invalid acl entry %q: missing type", s)
+	}
+	switch t := AclEntryType(strings.ToLower(fields[i])); t {
+	case AclEntryTypeUser, AclEntryTypeGroup, AclEntryTypeMask, AclEntryTypeOther:
+		e.Type = t
+	default:
+		return AclEntry{}, fmt.Errorf("invalid acl entry %q: unknown type %q", s, fields[i])
+	}
+	i++
+	if i < len(fields) {
+		e.Name = fields[i]
+		i++
+	}
+	if e.Name != "" && (e.Type == AclEntryTypeMask || e.Type == AclEntryTypeOther) {
+		return AclEntry{}, fmt.Errorf("invalid acl entry %q: %s entry must not have a name", s, e.Type)
+	}
+	if includePermission {
+		if i >= len(fields) {
+			return AclEntry{}, fmt.Errorf("invalid acl entry %q: missing permission", s)
+		}
+		perm, err := ParseFsAction(fields[i])
+		if err != nil {
+			return AclEntry{}, fmt.Errorf("invalid acl entry %q: %w", s, err)
+		}
+		e.Permission = perm
+		i++
+	}
+	if i < len(fields) {
+		return AclEntry{}, fmt.Errorf("invalid acl entry %q: too many fields", s)
+	}
+	return e, nil
+}
+
+// ParseAclSpec parses a comma separated list of entries in the aclspec syntax, see ParseAclEntry.
+func ParseAclSpec(spec string, includePermission bool) ([]AclEntry, error) {
+	var entries []AclEntry
+	for _, s := range strings.Split(spec, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		e, err := ParseAclEntry(s, includePermission)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// FormatAclSpec formats entries into a comma separated list in the aclspec syntax, with permissions if includePermission.
+func FormatAclSpec(entries []AclEntry, includePermission bool) string {
+	specs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if includePermission {
+			specs = append(specs, e.String())
+		} else {
+			specs = append(specs, e.spec())
+		}
+	}
+	return strings.Join(specs, ",")
+}
+
+// String returns the entry in the aclspec syntax, such as default:user:alice:rw-.
+func (e AclEntry) String() string {
+	return e.spec() + ":" + e.Permission.String()
+}
+
+// spec returns the entry in the aclspec syntax without permission, such as default:user:alice.
+func (e AclEntry) spec() string {
+	var b strings.Builder
+	if e.Scope == AclEntryScopeDefault {
+		b.WriteString("default:")
+	}
+	b.WriteString(string(e.Type))
+	b.WriteByte(':')
+	b.WriteString(e.Name)
+	return b.String()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for AclEntry
+func (e AclEntry) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for AclEntry
+func (e *AclEntry) UnmarshalText(text []byte) error {
+	entry, err := ParseAclEntry(string(text), true)
+	if err != nil {
+		return err
+	}
+	*e = entry
+	return nil
+}
+
+// FsAction
+// The file system action, a combination of read, write and execute, as FsAction of Hadoop.
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Fs_Action
+type FsAction uint8
+
+const (
+	FsActionNone         FsAction = 0 // ---
+	FsActionExecute      FsAction = 1 // --x
+	FsActionWrite        FsAction = 2 // -w-
+	FsActionWriteExecute FsAction = 3 // -wx
+	FsActionRead         FsAction = 4 // r--
+	FsActionReadExecute  FsAction = 5 // r-x
+	FsActionReadWrite    FsAction = 6 // rw-
+	FsActionAll          FsAction = 7 // rwx
+)
+
+// ParseFsAction parses an action in the symbolic syntax of [r-][w-][x-], such as r-x.
+func ParseFsAction(s string) (FsAction, error) {
+	if len(s) != 3 {
+		return 0, fmt.Errorf("invalid fs action %q", s)
+	}
+	var a FsAction
+	for i, c := range []byte("rwx") {
+		switch s[i] {
+		case c:
+			a |= 1 << (2 - i)
+		case '-':
+		default:
+			return 0, fmt.Errorf("invalid fs action %q", s)
+		}
+	}
+	return a, nil
+}
+
+// String returns the action in the symbolic syntax, such as r-x.
+func (a FsAction) String() string {
+	b := []byte("---")
+	for i, c := range []byte("rwx") {
+		if a&(1<<(2-i)) != 0 {
+			b[i] = c
+		}
+	}
+	return string(b)
+}
+
+func (a FsAction) New() *FsAction {
+	var c = a
+	return &c
+}
+
+// Implies reports whether a covers all actions of that, such as rwx implies r-x.
+func (a FsAction) Implies(that FsAction) bool {
+	return a&that == that
+}
+
+// And returns the actions of both a and that.
+func (a FsAction) And(that FsAction) FsAction {
+	return a & that
+}
+
+// Or returns the actions of either a or that.
+func (a FsAction) Or(that FsAction) FsAction {
+	return (a | that) & FsActionAll
+}
+
+// Not returns the actions not of a.
+func (a FsAction) Not() FsAction {
+	return ^a & FsActionAll
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for FsAction
+func (a FsAction) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for FsAction
+func (a *FsAction) UnmarshalText(text []byte) error {
+	action, err := ParseFsAction(string(text))
+	if err != nil {
+		return err
+	}
+	*a = action
+	return nil
+}
+
 // XAttr Name
 // The XAttr name of a file/directory.
 // Any string prefixed with user./trusted./system./security..
@@ -83,6 +308,15 @@ func (f XAttrValueEncoding) New() *XAttrValueEncoding {
 	return &c
 }
 
+// known reports whether f is one of the encodings defined.
+func (f XAttrValueEncoding) known() bool {
+	switch f {
+	case XAttrValueEncodingText, XAttrValueEncodingHex, XAttrValueEncodingBase64:
+		return true
+	}
+	return false
+}
+
 // Access Time
 // The access time of a file/directory.
 // See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Access_Time
@@ -94,3 +328,26 @@ func (f XAttrValueEncoding) New() *XAttrValueEncoding {
 // Create Flag
 // Enum of possible flags to process while creating a file
 // See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Create_Flag
+type CreateFlag string
+
+const (
+	CreateFlagCreate               CreateFlag = "CREATE"                 // create a file
+	CreateFlagOverwrite            CreateFlag = "OVERWRITE"              // truncate or overwrite a file, the file must exist
+	CreateFlagAppend               CreateFlag = "APPEND"                 // append to a file, the file must exist
+	CreateFlagSyncBlock            CreateFlag = "SYNC_BLOCK"             // force closed blocks to disk
+	CreateFlagLazyPersist          CreateFlag = "LAZY_PERSIST"           // create a block on transient storage, such as RAM_DISK, if possible
+	CreateFlagNewBlock             CreateFlag = "NEW_BLOCK"              // append data to a new block instead of the end of the last partial block
+	CreateFlagNoLocalWrite         CreateFlag = "NO_LOCAL_WRITE"         // advise not to place a replica on the local datanode
+	CreateFlagIgnoreClientLocality CreateFlag = "IGNORE_CLIENT_LOCALITY" // advise to ignore the locality of the client when placing replicas
+	CreateFlagShouldReplicate      CreateFlag = "SHOULD_REPLICATE"       // enforce replication, even if an erasure coding policy is inherited
+)
+
+// FormatCreateFlags formats flags into a comma separated list, such as CREATE,OVERWRITE.
+// The combinations of APPEND,CREATE and APPEND,CREATE,OVERWRITE are not valid.
+func FormatCreateFlags(flags []CreateFlag) string {
+	fs := make([]string, 0, len(flags))
+	for _, f := range flags {
+		fs = append(fs, string(f))
+	}
+	return strings.Join(fs, ",")
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/searKing/webhdfs"
+)
+
+func TestParseAclEntry(t *testing.T) {
+	tests := []struct {
+		s                 string
+		includePermission bool
+		want              webhdfs.AclEntry
+	}{
+		{"user::rwx", true, webhdfs.AclEntry{Type: webhdfs.AclEntryTypeUser, Permission: webhdfs.FsActionAll}},
+		{"user:alice:rw-", true, webhdfs.AclEntry{Type: webhdfs.AclEntryTypeUser, Name: "alice", Permission: webhdfs.FsActionReadWrite}},
+		{"group:staff:r--", true, webhdfs.AclEntry{Type: webhdfs.AclEntryTypeGroup, Name: "staff", Permission: webhdfs.FsActionRead}},
+		{"mask::r-x", true, webhdfs.AclEntry{Type: webhdfs.AclEntryTypeMask, Permission: webhdfs.FsActionReadExecute}},
+		{"other::---", true, webhdfs.AclEntry{Type: webhdfs.AclEntryTypeOther}},
+		{"USER:bob:--x", true, webhdfs.AclEntry{Type: webhdfs.AclEntryTypeUser, Name: "bob", Permission: webhdfs.FsActionExecute}},
+		{"default:user:bob:r-x", true, webhdfs.AclEntry{Scope: webhdfs.AclEntryScopeDefault, Type: webhdfs.AclEntryTypeUser, Name: "bob", Permission: webhdfs.FsActionReadExecute}},
+		{"default:mask::rw-", true, webhdfs.AclEntry{Scope: webhdfs.AclEntryScopeDefault, Type: webhdfs.AclEntryTypeMask, Permission: webhdfs.FsActionReadWrite}},
+		{"user:alice", false, webhdfs.AclEntry{Type: webhdfs.AclEntryTypeUser, Name: "alice"}},
+		{"default:group:staff", false, webhdfs.AclEntry{Scope: webhdfs.AclEntryScopeDefault, Type: webhdfs.AclEntryTypeGroup, Name: "staff"}},
+		{"default:mask", false, webhdfs.AclEntry{Scope: webhdfs.AclEntryScopeDefault, Type: webhdfs.AclEntryTypeMask}},
+		{"other:", false, webhdfs.AclEntry{Type: webhdfs.AclEntryTypeOther}},
+	}
+	for _, tt := range tests {
+		got, err := webhdfs.ParseAclEntry(tt.s, tt.includePermission)
+		if err != nil {
+			t.Errorf("ParseAclEntry(%q, %t) failed: %s", tt.s, tt.includePermission, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAclEntry(%q, %t): got %+v, want %+v", tt.s, tt.includePermission, got, tt.want)
+		}
+	}
+}
+
+func TestParseAclEntry_Invalid(t *testing.T) {
+	tests := []struct {
+		s                 string
+		includePermission bool
+	}{
+		{"", true},
+		{"default", true},
+		{"default:", true},
+		{"owner::rwx", true},
+		{"default:default:user::rwx", true},
+		{"mask:bob:rw-", true},
+		{"other:bob:r--", true},
+		{"default:mask:bob:rw-", true},
+		{"default:other:bob", false},
+		{"user:alice", true},
+		{"user:alice:", true},
+		{"user:alice:rwxr", true},
+		{"user:alice:rw-", false},
+		{"user:alice:rw-:x", true},
+	}
+	for _, tt := range tests {
+		if got, err := webhdfs.ParseAclEntry(tt.s, tt.includePermission); err == nil {
+			t.Errorf("ParseAclEntry(%q, %t): got %+v, want error", tt.s, tt.includePermission, got)
+		}
+	}
+}
+
+func TestParseAclSpec(t *testing.T) {
+	spec := "default:user:bob:r-x,mask::rw-"
+	want := []webhdfs.AclEntry{
+		{Scope: webhdfs.AclEntryScopeDefault, Type: webhdfs.AclEntryTypeUser, Name: "bob", Permission: webhdfs.FsActionReadExecute},
+		{Type: webhdfs.AclEntryTypeMask, Permission: webhdfs.FsActionReadWrite},
+	}
+	entries, err := webhdfs.ParseAclSpec(spec, true)
+	if err != nil {
+		t.Fatalf("webhdfs ParseAclSpec failed: %s", err)
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("ParseAclSpec(%q): got %+v, want %+v", spec, entries, want)
+	}
+	if got := webhdfs.FormatAclSpec(entries, true); got != spec {
+		t.Errorf("FormatAclSpec: got %q, want %q", got, spec)
+	}
+	if got := webhdfs.FormatAclSpec(entries, false); got != "default:user:bob,mask:" {
+		t.Errorf("FormatAclSpec without permission: got %q, want %q", got, "default:user:bob,mask:")
+	}
+
+	// spaces and empty entries are ignored
+	entries, err = webhdfs.ParseAclSpec(" default:user:bob:r-x, ,mask::rw-,", true)
+	if err != nil {
+		t.Fatalf("webhdfs ParseAclSpec failed: %s", err)
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("ParseAclSpec with spaces: got %+v, want %+v", entries, want)
+	}
+
+	// entries of REMOVEACLENTRIES
+	entries, err = webhdfs.ParseAclSpec("default:user:bob,mask:", false)
+	if err != nil {
+		t.Fatalf("webhdfs ParseAclSpec failed: %s", err)
+	}
+	if got := webhdfs.FormatAclSpec(entries, false); got != "default:user:bob,mask:" {
+		t.Errorf("FormatAclSpec without permission: got %q, want %q", got, "default:user:bob,mask:")
+	}
+
+	for _, spec := range []string{"default:user:bob:r-x,mask:bob:rw-", "user::rwx,other:bob:r--", "user::rwx,user:alice"} {
+		if entries, err := webhdfs.ParseAclSpec(spec, true); err == nil {
+			t.Errorf("ParseAclSpec(%q): got %+v, want error", spec, entries)
+		}
+	}
+}
+
+func TestAclEntry_UnmarshalText(t *testing.T) {
+	var e webhdfs.AclEntry
+	if err := e.UnmarshalText([]byte("default:user:bob:r-x")); err != nil {
+		t.Fatalf("webhdfs UnmarshalText failed: %s", err)
+	}
+	text, err := e.MarshalText()
+	if err != nil {
+		t.Fatalf("webhdfs MarshalText failed: %s", err)
+	}
+	if string(text) != "default:user:bob:r-x" {
+		t.Errorf("MarshalText: got %q, want %q", text, "default:user:bob:r-x")
+	}
+	if err := e.UnmarshalText([]byte("mask:bob:rw-")); err == nil {
+		t.Errorf("UnmarshalText(%q): got %+v, want error", "mask:bob:rw-", e)
+	}
+}
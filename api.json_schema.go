@@ -47,6 +47,15 @@ func (name XAttrNames) MarshalJSON() ([]byte, error) {
 	return []byte(strconv.Quote(string(data))), nil
 }
 
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#ACL_Status_JSON_Schema
+type AclStatus struct {
+	Entries    []AclEntry `json:"entries"`                        // The extended ACL entries, the owner, the group owner and the others are not included.
+	Group      string     `json:"group" validate:"required"`      // The group owner.
+	Owner      string     `json:"owner" validate:"required"`      // The user who is the owner.
+	Permission Permission `json:"permission" validate:"required"` // The permission represented as a octal string.
+	StickyBit  bool       `json:"stickyBit" validate:"required"`  // True if the sticky bit is on.
+}
+
 // See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Boolean_JSON_Schema
 type Boolean = bool // A boolean value.
 
@@ -87,6 +96,54 @@ type QuotaUsage struct {
 	TypeQuota             TypeQuota `json:"typeQuota" validate:"required"`
 }
 
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#FsServerDefaults_JSON_Schema
+type FsServerDefaults struct {
+	BlockSize              int64        `json:"blockSize" validate:"required"`           // The default block size of a file.
+	BytesPerChecksum       int64        `json:"bytesPerChecksum" validate:"required"`    // The number of bytes per checksum.
+	WritePacketSize        int64        `json:"writePacketSize" validate:"required"`     // The packet size of writing to datanodes.
+	Replication            int64        `json:"replication" validate:"required"`         // The default number of replication of a file.
+	FileBufferSize         int64        `json:"fileBufferSize" validate:"required"`      // The buffer size of reading and writing files.
+	EncryptDataTransfer    bool         `json:"encryptDataTransfer" validate:"required"` // True if the data transfer is encrypted.
+	TrashInterval          int64        `json:"trashInterval" validate:"required"`       // The minutes after which a checkpoint of trash is deleted, 0 if trash is disabled.
+	ChecksumType           ChecksumType `json:"checksumType" validate:"required"`        // The checksum type.
+	KeyProviderUri         string       `json:"keyProviderUri"`                          // The uri of the encryption key provider, empty if not configured.
+	DefaultStoragePolicyId int64        `json:"defaultStoragePolicyId"`                  // The id of the default storage policy.
+}
+
+// ChecksumType is the type of checksum of data, as DataChecksum.Type of Hadoop, encoded as its id.
+type ChecksumType int
+
+const (
+	ChecksumTypeNull    ChecksumType = 0 // NULL
+	ChecksumTypeCRC32   ChecksumType = 1 // CRC32
+	ChecksumTypeCRC32C  ChecksumType = 2 // CRC32C
+	ChecksumTypeDefault ChecksumType = 3 // DEFAULT, resolved by the datanode to CRC32C
+	ChecksumTypeMixed   ChecksumType = 4 // MIXED, of the blocks with different checksum types
+)
+
+func (t ChecksumType) String() string {
+	switch t {
+	case ChecksumTypeNull:
+		return "NULL"
+	case ChecksumTypeCRC32:
+		return "CRC32"
+	case ChecksumTypeCRC32C:
+		return "CRC32C"
+	case ChecksumTypeDefault:
+		return "DEFAULT"
+	case ChecksumTypeMixed:
+		return "MIXED"
+	}
+	return "ChecksumType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#FsStatus_JSON_Schema
+type FsStatus struct {
+	Capacity  int64 `json:"capacity" validate:"required"`  // The capacity of the file system, in bytes.
+	Used      int64 `json:"used" validate:"required"`      // The space used, in bytes.
+	Remaining int64 `json:"remaining" validate:"required"` // The space remaining, in bytes.
+}
+
 // See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#FileChecksum_JSON_Schema
 type FileChecksum struct {
 	Algorithm string `json:"algorithm" validate:"required"` // The name of the checksum algorithm.
@@ -141,9 +198,12 @@ func (fi *FileStatusProperties) Size() int64 {
 }
 
 func (fi *FileStatusProperties) Mode() os.FileMode {
-	mode := os.FileMode(fi.Permission)
-	if fi.IsDir() {
+	mode := fi.Permission.fileMode()
+	switch fi.Type {
+	case FileTypeDirectory:
 		mode |= os.ModeDir
+	case FileTypeSymlink:
+		mode |= os.ModeSymlink
 	}
 
 	return mode
@@ -261,6 +321,9 @@ const (
 	JavaClassNamePathIsNotEmptyDirectoryException = "org.apache.hadoop.fs.PathIsNotEmptyDirectoryException"
 	JavaClassNameFileAlreadyExistsException       = "org.apache.hadoop.fs.FileAlreadyExistsException"
 	JavaClassNameAlreadyBeingCreatedException     = "org.apache.hadoop.hdfs.protocol.AlreadyBeingCreatedException"
+	JavaClassNameRecoveryInProgressException      = "org.apache.hadoop.hdfs.protocol.RecoveryInProgressException"
+	JavaClassNameRetriableException               = "org.apache.hadoop.ipc.RetriableException"
+	JavaClassNameStandbyException                 = "org.apache.hadoop.ipc.StandbyException"
 )
 
 func (e *RemoteException) Unwrap() error {
@@ -353,6 +416,48 @@ const (
 	DiffReportEntryTypeRename DiffReportEntryType = "RENAME"
 )
 
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#SnapshotDiffReportListing_JSON_Schema
+type SnapshotDiffReportListing struct {
+	CreateList    []DiffReportListingEntry `json:"createList" validate:"required"`    // An array of DiffReportListingEntry of the files created.
+	DeleteList    []DiffReportListingEntry `json:"deleteList" validate:"required"`    // An array of DiffReportListingEntry of the files deleted.
+	ModifyList    []DiffReportListingEntry `json:"modifyList" validate:"required"`    // An array of DiffReportListingEntry of the files modified or renamed.
+	IsFromEarlier bool                     `json:"isFromEarlier" validate:"required"` // True if the old snapshot is earlier than the new one.
+	LastIndex     int64                    `json:"lastIndex" validate:"required"`     // Index in the last directory listed, -1 if the listing is done.
+	LastPath      string                   `json:"lastPath" validate:"required"`      // The last directory listed, empty if the listing is done.
+}
+
+// Done reports whether the listing is done, that is no more pages to get.
+func (l *SnapshotDiffReportListing) Done() bool {
+	return l.LastPath == "" && l.LastIndex == -1
+}
+
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#DiffReportListing_Entries
+type DiffReportListingEntry struct {
+	DirId       int64  `json:"dirId" validate:"required"`       // Inode id of the parent directory.
+	FileId      int64  `json:"fileId" validate:"required"`      // Inode id of the file.
+	IsReference bool   `json:"isReference" validate:"required"` // True if the file is a reference, as renamed.
+	SourcePath  string `json:"sourcePath"`                      // Source path name relative to snapshot root.
+	TargetPath  string `json:"targetPath"`                      // Target path relative to snapshot root used for renames.
+}
+
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#SnapshotList_JSON_Schema
+type SnapshotList = []SnapshotStatus // An array of SnapshotStatus
+
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#SnapshotStatus
+type SnapshotStatus struct {
+	DirStatus      FileStatusProperties   `json:"dirStatus"`                          // Status of the snapshot directory.
+	SnapshotID     int64                  `json:"snapshotID" validate:"required"`     // Id of the snapshot.
+	DeletionStatus SnapshotDeletionStatus `json:"deletionStatus" validate:"required"` // Whether the snapshot is deleted, ["ACTIVE", "DELETED"]
+	FullPath       string                 `json:"fullPath" validate:"required"`       // Full path of the snapshot, such as /dir/.snapshot/s0.
+}
+
+type SnapshotDeletionStatus string
+
+const (
+	SnapshotDeletionStatusActive  SnapshotDeletionStatus = "ACTIVE"
+	SnapshotDeletionStatusDeleted SnapshotDeletionStatus = "DELETED"
+)
+
 // See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#SnapshottableDirectoryList_JSON_Schema
 type SnapshottableDirectoryList = []SnapshottableDirectoryStatus // An array of SnapshottableDirectoryStatus
 
@@ -365,7 +470,7 @@ type SnapshottableDirectoryStatus struct {
 }
 
 // See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#BlockLocations_JSON_Schema
-type BlockLocations = struct {
+type BlockLocations struct {
 	BlockLocations []BlockLocation `json:"BlockLocation"` // An array of BlockLocation
 }
 
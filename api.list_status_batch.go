@@ -56,6 +56,9 @@ func (req *ListStatusBatchRequest) RawQuery() string {
 	if req.ProxyUser.DoAs != nil {
 		v.Set("doas", types.Value(req.ProxyUser.DoAs))
 	}
+	if req.StartAfter != nil {
+		v.Set("startAfter", types.Value(req.StartAfter))
+	}
 
 	return v.Encode()
 }
@@ -36,6 +36,14 @@ type MkdirsRequest struct {
 	// Valid Values		0 - 1777
 	// Syntax			Any radix-8 integer (leading zeros may be omitted.)
 	Permission *int
+	// Name				unmaskedpermission
+	// Description		The permission of a file/directory, not masked by the umask,
+	//					applied if the parent directory has a default ACL.
+	// Type				Octal
+	// Default Value	null
+	// Valid Values		0 - 1777
+	// Syntax			Any radix-8 integer (leading zeros may be omitted.)
+	UnmaskedPermission *int
 }
 
 type MkdirsResponse struct {
@@ -64,6 +72,9 @@ func (req *MkdirsRequest) RawQuery() string {
 	if req.Permission != nil {
 		v.Set("permission", fmt.Sprintf("%#o", types.Value(req.Permission)))
 	}
+	if req.UnmaskedPermission != nil {
+		v.Set("unmaskedpermission", fmt.Sprintf("%#o", types.Value(req.UnmaskedPermission)))
+	}
 	return v.Encode()
 }
 
@@ -0,0 +1,154 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/searKing/golang/go/exp/types"
+
+	"github.com/searKing/golang/go/errors"
+)
+
+type RemoveAclEntriesRequest struct {
+	Authentication
+	ProxyUser
+	CSRF
+	HttpRequest
+
+	// Path of the object to get.
+	//
+	// Path is a required field
+	Path *string `validate:"required"`
+
+	// Name				aclspec
+	// Description		The ACL spec included in ACL modification operations.
+	// Type				String
+	// Default Value	<empty>
+	// Valid Values		See Permissions and HDFS.
+	// Syntax			See Permissions and HDFS.
+	// Permissions of the entries are left out, as only the scope, type and name are to match.
+	AclSpec []AclEntry `validate:"required"`
+}
+
+type RemoveAclEntriesResponse struct {
+	NameNode string `json:"-"`
+	ErrorResponse
+	HttpResponse `json:"-"`
+}
+
+func (req *RemoveAclEntriesRequest) RawPath() string {
+	return types.Value(req.Path)
+}
+func (req *RemoveAclEntriesRequest) RawQuery() string {
+	v := url.Values{}
+	v.Set("op", OpRemoveAclEntries)
+	if req.Authentication.Delegation != nil {
+		v.Set("delegation", types.Value(req.Authentication.Delegation))
+	}
+	if req.ProxyUser.Username != nil {
+		v.Set("user.name", types.Value(req.ProxyUser.Username))
+	}
+	if req.ProxyUser.DoAs != nil {
+		v.Set("doas", types.Value(req.ProxyUser.DoAs))
+	}
+
+	if len(req.AclSpec) > 0 {
+		v.Set("aclspec", FormatAclSpec(req.AclSpec, false))
+	}
+	return v.Encode()
+}
+
+func (resp *RemoveAclEntriesResponse) UnmarshalHTTP(httpResp *http.Response) error {
+	resp.HttpResponse.UnmarshalHTTP(httpResp)
+	if isSuccessHttpCode(httpResp.StatusCode) {
+		return nil
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return ErrorFromHttpResponse(httpResp)
+	}
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	if err := resp.Exception(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Remove ACL Entries
+// See: https://hadoop.apache.org/docs/current/hadoop-project-dist/hadoop-hdfs/WebHDFS.html#Remove_ACL_Entries
+func (c *Client) RemoveAclEntries(req *RemoveAclEntriesRequest) (*RemoveAclEntriesResponse, error) {
+	return c.removeAclEntries(nil, req)
+}
+func (c *Client) RemoveAclEntriesWithContext(ctx context.Context, req *RemoveAclEntriesRequest) (*RemoveAclEntriesResponse, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	return c.removeAclEntries(ctx, req)
+}
+func (c *Client) removeAclEntries(ctx context.Context, req *RemoveAclEntriesRequest) (*RemoveAclEntriesResponse, error) {
+	err := c.opts.Validator.Struct(req)
+	if err != nil {
+		return nil, err
+	}
+
+	nameNodes := c.opts.Addresses
+	if nameNodes == nil {
+		return nil, fmt.Errorf("missing namenode addresses")
+	}
+	var u = c.HttpUrl(req)
+
+	var errs []error
+	for _, addr := range nameNodes {
+		u.Host = addr
+
+		httpReq, err := http.NewRequest(http.MethodPut, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Close = req.HttpRequest.Close
+		if req.CSRF.XXsrfHeader != nil {
+			httpReq.Header.Set("X-XSRF-HEADER", types.Value(req.CSRF.XXsrfHeader))
+		}
+
+		if ctx != nil {
+			httpReq = httpReq.WithContext(ctx)
+		}
+		if req.HttpRequest.PreSendHandler != nil {
+			httpReq, err = req.HttpRequest.PreSendHandler(httpReq)
+			if err != nil {
+				return nil, fmt.Errorf("pre send handled: %w", err)
+			}
+		}
+
+		httpResp, err := c.httpClient().Do(httpReq)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var resp RemoveAclEntriesResponse
+		resp.NameNode = addr
+
+		if err := resp.UnmarshalHTTP(httpResp); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return &resp, nil
+	}
+	return nil, errors.Multi(errs...)
+}
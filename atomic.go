@@ -0,0 +1,106 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// WriteFileAtomic writes the content of r to the file name with the permission perm,
+// so that readers see either the old content or the new one as a whole, never a file half-written.
+//
+// The content is written to a hidden temporary file in the same directory, such as .name.<nanos>.tmp,
+// whose length is verified against the bytes read from r, then renamed to name.
+// As RENAME of WebHDFS never overwrites, an existing file is renamed aside to .name.<nanos>.bak first,
+// and renamed back if the temporary file fails to take its place, then deleted once replaced.
+// So name is missing for a moment while being replaced, but never corrupted, and is left intact on failure.
+// An error wrapping syscall.EISDIR is returned if name is a directory.
+func (c *Client) WriteFileAtomic(ctx context.Context, name string, r io.Reader, perm Permission) error {
+	if ctx == nil {
+		panic("nil context")
+	}
+	tmp := hiddenSibling(name, "tmp")
+	if err := c.writeTemp(ctx, tmp, r, perm); err != nil {
+		c.discard(tmp)
+		return &fs.PathError{Op: "write", Path: name, Err: err}
+	}
+	if err := c.replaceFile(ctx, tmp, name); err != nil {
+		c.discard(tmp)
+		return &fs.PathError{Op: "replace", Path: name, Err: err}
+	}
+	return nil
+}
+
+// writeTemp writes the content of r to the new file tmp with the permission perm, and verifies its length.
+func (c *Client) writeTemp(ctx context.Context, tmp string, r io.Reader, perm Permission) error {
+	w := c.NewWriter(ctx, tmp, WithWriterPermission(perm))
+	n, err := io.Copy(w, r)
+	if err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	info, err := c.fileStatus(ctx, tmp)
+	if err != nil {
+		return err
+	}
+	if info.Length != n {
+		return fmt.Errorf("%d bytes read to be written to %s, but %d bytes got", n, tmp, info.Length)
+	}
+	// the permission of CREATE is masked by fs.permissions.umask-mode
+	return c.setFilePermission(ctx, tmp, perm)
+}
+
+// replaceFile renames src to dst, replacing dst if exists, by way of a backup renamed back on failure.
+// A directory at dst is never replaced, but an error wrapping syscall.EISDIR is returned.
+func (c *Client) replaceFile(ctx context.Context, src, dst string) error {
+	if info, err := c.fileStatus(ctx, dst); err == nil && info.IsDir() {
+		return fmt.Errorf("replace %s: %w", dst, syscall.EISDIR)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	err := c.renameFile(ctx, src, dst)
+	if err == nil || !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+
+	backup := hiddenSibling(dst, "bak")
+	if err := c.renameFile(ctx, dst, backup); err != nil {
+		return fmt.Errorf("rename existing file aside: %w", err)
+	}
+	if err := c.renameFile(ctx, src, dst); err != nil {
+		// restored even if ctx is done, as the rename may have failed for that
+		if rerr := c.renameFile(context.Background(), backup, dst); rerr != nil {
+			return fmt.Errorf("%w, and failed to restore the existing file from %s: %s", err, backup, rerr)
+		}
+		return err
+	}
+	c.discard(backup)
+	return nil
+}
+
+// discard deletes the file name, ignoring any error.
+// The file is deleted even if ctx is done, as the write may have failed for that.
+func (c *Client) discard(name string) {
+	_, _ = c.deleteFile(context.Background(), name, false)
+}
+
+// hiddenSibling returns the path of a hidden file in the same directory as name, unique per call,
+// such as .name.<nanos>.ext.
+func hiddenSibling(name string, ext string) string {
+	dir, file := path.Split(name)
+	return path.Join(dir, "."+file+"."+strconv.FormatInt(time.Now().UnixNano(), 36)+"."+ext)
+}
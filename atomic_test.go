@@ -0,0 +1,156 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+// renameServer is a namenode of files and directories in memory, renaming as RENAME of HDFS does,
+// moving src into dst if a directory, and answering false if src or the parent of dst does not exist, or dst exists.
+type renameServer struct {
+	mu    sync.Mutex
+	files map[string]string
+	dirs  map[string]bool
+}
+
+func (s *renameServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := path.Clean(strings.TrimPrefix(r.URL.Path, "/webhdfs/v1"))
+	q := r.URL.Query()
+	w.Header().Set("Content-Type", "application/json")
+	switch q.Get("op") {
+	case OpGetFileStatus:
+		data, isFile := s.files[p]
+		switch {
+		case isFile:
+			fmt.Fprintf(w, `{"FileStatus":{"length":%d,"type":"FILE","permission":"644"}}`, len(data))
+		case s.dirs[p]:
+			fmt.Fprint(w, `{"FileStatus":{"length":0,"type":"DIRECTORY","permission":"755"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"RemoteException":{"exception":"FileNotFoundException","javaClassName":"java.io.FileNotFoundException","message":"File does not exist: %s"}}`, p)
+		}
+	case OpCreate:
+		data, _ := ioutil.ReadAll(r.Body)
+		s.files[p] = string(data)
+		w.WriteHeader(http.StatusCreated)
+	case OpSetPermission:
+	case OpRename:
+		dst := path.Clean(q.Get("destination"))
+		if s.dirs[dst] {
+			dst = path.Join(dst, path.Base(p))
+		}
+		data, isFile := s.files[p]
+		_, dstFile := s.files[dst]
+		ok := isFile && !dstFile && !s.dirs[dst] && s.dirs[path.Dir(dst)]
+		if ok {
+			delete(s.files, p)
+			s.files[dst] = data
+		}
+		json.NewEncoder(w).Encode(map[string]bool{"boolean": ok})
+	case OpDelete:
+		_, ok := s.files[p]
+		delete(s.files, p)
+		json.NewEncoder(w).Encode(map[string]bool{"boolean": ok})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"RemoteException":{"exception":"IllegalArgumentException","javaClassName":"java.lang.IllegalArgumentException","message":"unexpected op %s"}}`, q.Get("op"))
+	}
+}
+
+// names returns the paths of all files, sorted.
+func (s *renameServer) names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newRenameClient(t *testing.T, s *renameServer) *Client {
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	c, err := New(strings.TrimPrefix(srv.URL, "http://"), WithDisableSSL(true), WithKerberosConfig(nil))
+	if err != nil {
+		t.Fatalf("create client %s", err)
+	}
+	return c
+}
+
+func TestClient_WriteFileAtomic_Directory(t *testing.T) {
+	s := &renameServer{files: map[string]string{"/d/f": "kept"}, dirs: map[string]bool{"/": true, "/d": true}}
+	c := newRenameClient(t, s)
+
+	err := c.WriteFileAtomic(context.Background(), "/d", strings.NewReader("Hello World!"), 0644)
+	if !errors.Is(err, syscall.EISDIR) {
+		t.Errorf("WriteFileAtomic to a directory: got %v, want %v", err, syscall.EISDIR)
+	}
+	if got, want := s.names(), []string{"/d/f"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("files left: got %q, want %q", got, want)
+	}
+
+	if err := c.WriteFileAtomic(context.Background(), "/d/f", strings.NewReader("Hello World!"), 0644); err != nil {
+		t.Fatalf("webhdfs WriteFileAtomic failed: %s", err)
+	}
+	if got := s.files["/d/f"]; got != "Hello World!" {
+		t.Errorf("expected %q, got %q", "Hello World!", got)
+	}
+	if got, want := s.names(), []string{"/d/f"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("files left: got %q, want %q", got, want)
+	}
+}
+
+func TestClient_renameFile(t *testing.T) {
+	s := &renameServer{
+		files: map[string]string{"/a": "a", "/b": "b"},
+		dirs:  map[string]bool{"/": true, "/d": true},
+	}
+	c := newRenameClient(t, s)
+	ctx := context.Background()
+
+	tests := []struct {
+		src, dst string
+		want     error
+	}{
+		{"/a", "/b", fs.ErrExist},
+		{"/a", "/d", fs.ErrExist},
+		{"/missing", "/c", fs.ErrNotExist},
+		{"/a", "/missing/c", fs.ErrNotExist},
+	}
+	for _, tt := range tests {
+		err := c.renameFile(ctx, tt.src, tt.dst)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("renameFile(%q, %q): got %v, want %v", tt.src, tt.dst, err, tt.want)
+		}
+	}
+	if got, want := s.names(), []string{"/a", "/b"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("files after failed renames: got %q, want %q", got, want)
+	}
+
+	if err := c.renameFile(ctx, "/a", "/d/a"); err != nil {
+		t.Fatalf("webhdfs renameFile failed: %s", err)
+	}
+	if got, want := s.names(), []string{"/b", "/d/a"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("files after rename: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"net"
+	"path"
+)
+
+// Range returns the blocks overlapping the byte range [offset, offset+length) of the file, in order of offset,
+// to the end of the file if length is negative.
+func (l *BlockLocations) Range(offset, length int64) []BlockLocation {
+	var blocks []BlockLocation
+	for _, b := range l.BlockLocations {
+		if b.Overlap(offset, length) > 0 {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// CorruptBlocks returns the blocks flagged corrupt, all replicas of which are corrupted, so that cannot be read.
+func (l *BlockLocations) CorruptBlocks() []BlockLocation {
+	var blocks []BlockLocation
+	for _, b := range l.BlockLocations {
+		if b.Corrupt {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// End returns the offset of the byte following the block.
+func (b *BlockLocationProperties) End() int64 {
+	return b.Offset + b.Length
+}
+
+// Overlap returns the number of bytes of the block within the byte range [offset, offset+length),
+// to the end of the file if length is negative.
+func (b *BlockLocationProperties) Overlap(offset, length int64) int64 {
+	start, end := b.Offset, b.End()
+	if offset > start {
+		start = offset
+	}
+	if length >= 0 && offset+length < end {
+		end = offset + length
+	}
+	if end < start {
+		return 0
+	}
+	return end - start
+}
+
+// Racks returns the rack of every replica, in the order of Hosts, such as /default-rack,
+// parsed from TopologyPaths, such as /default-rack/10.0.0.1:9866. An empty rack is returned if unknown.
+func (b *BlockLocationProperties) Racks() []string {
+	racks := make([]string, len(b.Hosts))
+	for i := range racks {
+		if i < len(b.TopologyPaths) {
+			racks[i] = path.Dir(b.TopologyPaths[i])
+		}
+	}
+	return racks
+}
+
+// BestHost returns the host the block is best read from by a client on host in rack, either may be empty if unknown.
+// The host itself is preferred, then a host in the same rack, then any other one,
+// and at each level a host with a replica cached in memory, see CachedHosts.
+// Hosts are matched by Hosts as well as the host of Names, so that host may be a hostname or an ip.
+// Ties are broken by the order of Hosts, which the namenode sorts by the distance to the client requested.
+// An empty string is returned if the block has no host.
+func (b *BlockLocationProperties) BestHost(host, rack string) string {
+	cached := make(map[string]bool, len(b.CachedHosts))
+	for _, h := range b.CachedHosts {
+		cached[h] = true
+	}
+	racks := b.Racks()
+
+	best, bestScore := "", 0
+	for i, h := range b.Hosts {
+		score := 4 // off rack
+		switch {
+		case host != "" && (h == host || b.nameHost(i) == host):
+			score = 0
+		case rack != "" && racks[i] == rack:
+			score = 2
+		}
+		if !cached[h] {
+			score++
+		}
+		if best == "" || score < bestScore {
+			best, bestScore = h, score
+		}
+	}
+	return best
+}
+
+// nameHost returns the host of the i-th of Names, such as 10.0.0.1 of 10.0.0.1:9866.
+func (b *BlockLocationProperties) nameHost(i int) string {
+	if i >= len(b.Names) {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(b.Names[i])
+	if err != nil {
+		return b.Names[i]
+	}
+	return host
+}
@@ -0,0 +1,264 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"strings"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+// ChecksumMismatchError is returned when the checksum of a local copy differs from the one of the HDFS file.
+type ChecksumMismatchError struct {
+	Path     string       // HDFS path of the file
+	Expected FileChecksum // checksum got by GETFILECHECKSUM
+	Actual   FileChecksum // checksum computed locally
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch of %s: expected %s %s, actual %s %s",
+		e.Path, e.Expected.Algorithm, e.Expected.Bytes, e.Actual.Algorithm, e.Actual.Bytes)
+}
+
+// ChecksumCombineMode is how the checksum of a file is combined from the CRCs of its chunks,
+// as dfs.checksum.combine.mode of Hadoop.
+type ChecksumCombineMode int
+
+const (
+	// ChecksumCombineModeMD5MD5CRC is the MD5 of the MD5 of the CRCs of every block, MD5MD5CRC of Hadoop.
+	// It depends on the block size and bytes per checksum a file is written with.
+	ChecksumCombineModeMD5MD5CRC ChecksumCombineMode = iota
+	// ChecksumCombineModeCompositeCRC is the CRC of the whole file, COMPOSITE_CRC of Hadoop.
+	// It depends on the content only, and so is comparable among files of different block sizes, even out of HDFS.
+	ChecksumCombineModeCompositeCRC
+)
+
+const (
+	md5md5crcAlgorithmFormat    = "MD5-of-%dMD5-of-%d%s" // crcPerBlock, bytesPerCRC and crcType
+	compositeCRCAlgorithmPrefix = "COMPOSITE-"
+	md5md5crcChecksumLength     = 4 + 8 + md5.Size // bytesPerCRC, crcPerBlock and MD5, as MD5MD5CRC32FileChecksum.write of Hadoop
+	compositeCRCChecksumLength  = 4
+)
+
+// DecodedFileChecksum is a FileChecksum decoded,
+// as MD5MD5CRC32FileChecksum or CompositeCrcFileChecksum of Hadoop, told by CombineMode.
+type DecodedFileChecksum struct {
+	CombineMode ChecksumCombineMode
+	CrcType     ChecksumType // CRC32 or CRC32C
+
+	BytesPerCRC int32          // the number of bytes per CRC, of ChecksumCombineModeMD5MD5CRC
+	CRCPerBlock int64          // the number of CRCs per block, 0 for a file of a single block, of ChecksumCombineModeMD5MD5CRC
+	MD5         [md5.Size]byte // the MD5 of the MD5 of the CRCs of every block, of ChecksumCombineModeMD5MD5CRC
+
+	CRC uint32 // the CRC of the whole file, of ChecksumCombineModeCompositeCRC
+}
+
+// Decode parses the checksum of the algorithm MD5-of-<x>MD5-of-<y><CRC32|CRC32C>, or COMPOSITE-<CRC32|CRC32C>.
+func (s FileChecksum) Decode() (DecodedFileChecksum, error) {
+	data, err := hex.DecodeString(checksumHex(s))
+	if err != nil {
+		return DecodedFileChecksum{}, fmt.Errorf("invalid checksum bytes %q: %w", s.Bytes, err)
+	}
+
+	if name := strings.TrimPrefix(s.Algorithm, compositeCRCAlgorithmPrefix); name != s.Algorithm {
+		crcType, ok := crcTypeOf(name)
+		if !ok {
+			return DecodedFileChecksum{}, fmt.Errorf("unsupported checksum algorithm %q", s.Algorithm)
+		}
+		if len(data) != compositeCRCChecksumLength {
+			return DecodedFileChecksum{}, fmt.Errorf("invalid checksum bytes of %s, %d bytes got", s.Algorithm, len(data))
+		}
+		return DecodedFileChecksum{
+			CombineMode: ChecksumCombineModeCompositeCRC,
+			CrcType:     crcType,
+			CRC:         binary.BigEndian.Uint32(data),
+		}, nil
+	}
+
+	var crcPerBlock int64
+	var bytesPerCRC int32
+	var name string
+	if _, err := fmt.Sscanf(s.Algorithm, md5md5crcAlgorithmFormat, &crcPerBlock, &bytesPerCRC, &name); err != nil {
+		return DecodedFileChecksum{}, fmt.Errorf("unsupported checksum algorithm %q", s.Algorithm)
+	}
+	crcType, ok := crcTypeOf(name)
+	if !ok {
+		return DecodedFileChecksum{}, fmt.Errorf("unsupported checksum algorithm %q", s.Algorithm)
+	}
+	if len(data) != md5md5crcChecksumLength {
+		return DecodedFileChecksum{}, fmt.Errorf("invalid checksum bytes of %s, %d bytes got", s.Algorithm, len(data))
+	}
+	d := DecodedFileChecksum{
+		CombineMode: ChecksumCombineModeMD5MD5CRC,
+		CrcType:     crcType,
+		BytesPerCRC: int32(binary.BigEndian.Uint32(data)),
+		CRCPerBlock: int64(binary.BigEndian.Uint64(data[4:])),
+	}
+	copy(d.MD5[:], data[12:])
+	if d.BytesPerCRC != bytesPerCRC || d.CRCPerBlock != crcPerBlock {
+		return DecodedFileChecksum{}, fmt.Errorf("checksum bytes of %d bytes per crc and %d crcs per block mismatch the algorithm %s",
+			d.BytesPerCRC, d.CRCPerBlock, s.Algorithm)
+	}
+	return d, nil
+}
+
+// Algorithm returns the name of the checksum algorithm, such as MD5-of-0MD5-of-512CRC32C or COMPOSITE-CRC32C.
+func (d DecodedFileChecksum) Algorithm() string {
+	if d.CombineMode == ChecksumCombineModeCompositeCRC {
+		return compositeCRCAlgorithmPrefix + d.CrcType.String()
+	}
+	return fmt.Sprintf(md5md5crcAlgorithmFormat, d.CRCPerBlock, d.BytesPerCRC, d.CrcType)
+}
+
+// Encode returns the checksum as GETFILECHECKSUM does.
+func (d DecodedFileChecksum) Encode() FileChecksum {
+	var data []byte
+	if d.CombineMode == ChecksumCombineModeCompositeCRC {
+		data = make([]byte, compositeCRCChecksumLength)
+		binary.BigEndian.PutUint32(data, d.CRC)
+	} else {
+		data = make([]byte, md5md5crcChecksumLength)
+		binary.BigEndian.PutUint32(data, uint32(d.BytesPerCRC))
+		binary.BigEndian.PutUint64(data[4:], uint64(d.CRCPerBlock))
+		copy(data[12:], d.MD5[:])
+	}
+	return FileChecksum{
+		Algorithm: d.Algorithm(),
+		Bytes:     hex.EncodeToString(data),
+		Length:    int64(len(data)),
+	}
+}
+
+// ComputeFileChecksum computes the checksum of the content of r locally, the same as GETFILECHECKSUM returns
+// for a file of the content written with blockSize and bytesPerChecksum, combined in mode from the CRCs of crcType.
+// The content is split into blocks of blockSize, and every block into chunks of bytesPerChecksum.
+// For ChecksumCombineModeMD5MD5CRC, the CRC of every chunk is digested into the MD5 of its block,
+// and the MD5 of all blocks into the MD5 of the file.
+// For ChecksumCombineModeCompositeCRC, the CRCs are combined into the CRC of the whole content,
+// which blockSize and bytesPerChecksum make no difference to.
+func ComputeFileChecksum(r io.Reader, mode ChecksumCombineMode, crcType ChecksumType, blockSize int64, bytesPerChecksum int32) (DecodedFileChecksum, error) {
+	table, ok := crcTable(crcType)
+	if !ok {
+		return DecodedFileChecksum{}, fmt.Errorf("unsupported checksum type %s", crcType)
+	}
+	if mode == ChecksumCombineModeCompositeCRC {
+		h := crc32.New(table)
+		if _, err := io.Copy(h, r); err != nil {
+			return DecodedFileChecksum{}, err
+		}
+		return DecodedFileChecksum{CombineMode: mode, CrcType: crcType, CRC: h.Sum32()}, nil
+	}
+	if bytesPerChecksum <= 0 || blockSize <= 0 {
+		return DecodedFileChecksum{}, fmt.Errorf("invalid checksum parameters, %d bytes per crc of block size %d", bytesPerChecksum, blockSize)
+	}
+
+	blockMD5 := md5.New()
+	chunk := make([]byte, bytesPerChecksum)
+	var crc [4]byte
+	var blockMD5s []byte
+	for {
+		n, err := blockChecksum(blockMD5, io.LimitReader(r, blockSize), chunk, table, crc[:])
+		if err != nil {
+			return DecodedFileChecksum{}, err
+		}
+		if n == 0 {
+			break
+		}
+		blockMD5s = blockMD5.Sum(blockMD5s)
+		blockMD5.Reset()
+		if n < blockSize {
+			break
+		}
+	}
+
+	d := DecodedFileChecksum{CombineMode: mode, CrcType: crcType, BytesPerCRC: bytesPerChecksum}
+	// taken from the first block by Hadoop, only if the file has more than one block
+	if blocks := len(blockMD5s) / md5.Size; blocks > 1 {
+		d.CRCPerBlock = (blockSize + int64(bytesPerChecksum) - 1) / int64(bytesPerChecksum)
+	}
+	// Hadoop digests the whole backing array of the DataOutputBuffer the MD5 of blocks are written to,
+	// which starts at 32 bytes and doubles as needed, zero padded beyond the MD5 written.
+	size := 32
+	for size < len(blockMD5s) {
+		size *= 2
+	}
+	d.MD5 = md5.Sum(append(blockMD5s, make([]byte, size-len(blockMD5s))...))
+	return d, nil
+}
+
+// crcTypeOf returns the CRC type named name, CRC32 or CRC32C.
+func crcTypeOf(name string) (ChecksumType, bool) {
+	for _, t := range []ChecksumType{ChecksumTypeCRC32, ChecksumTypeCRC32C} {
+		if name == t.String() {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// crcTable returns the table of the CRC type t, CRC32 or CRC32C.
+func crcTable(t ChecksumType) (*crc32.Table, bool) {
+	switch t {
+	case ChecksumTypeCRC32:
+		return crc32.IEEETable, true
+	case ChecksumTypeCRC32C:
+		return crc32.MakeTable(crc32.Castagnoli), true
+	}
+	return nil, false
+}
+
+// blockChecksum digests the CRC of every chunk of a block read from r into h, and returns the length of the block.
+func blockChecksum(h hash.Hash, r io.Reader, chunk []byte, table *crc32.Table, crc []byte) (int64, error) {
+	var n int64
+	for {
+		m, err := io.ReadFull(r, chunk)
+		if m > 0 {
+			binary.BigEndian.PutUint32(crc, crc32.Checksum(chunk[:m], table))
+			h.Write(crc)
+			n += int64(m)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// equalChecksum reports whether a and b are the same checksum.
+func equalChecksum(a, b FileChecksum) bool {
+	return a.Algorithm == b.Algorithm && strings.EqualFold(checksumHex(a), checksumHex(b))
+}
+
+// checksumHex returns the bytes of the checksum in hexadecimal, without the zeros padded beyond Length,
+// as HDFS sends the whole backing array the checksum is serialized to, such as 32 bytes of a checksum of 28 bytes.
+func checksumHex(s FileChecksum) string {
+	if s.Length > 0 && int64(len(s.Bytes)) > 2*s.Length {
+		return s.Bytes[:2*s.Length]
+	}
+	return s.Bytes
+}
+
+// fileChecksum returns the checksum of the file path got by GETFILECHECKSUM.
+func (c *Client) fileChecksum(ctx context.Context, path string) (FileChecksum, error) {
+	resp, err := c.GetFileChecksumWithContext(ctx, &GetFileChecksumRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+	})
+	if err != nil {
+		return FileChecksum{}, err
+	}
+	defer resp.Body.Close()
+	return resp.FileChecksum, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/searKing/webhdfs"
+)
+
+func TestComputeFileChecksum(t *testing.T) {
+	tests := []struct {
+		name             string
+		data             []byte
+		mode             webhdfs.ChecksumCombineMode
+		crcType          webhdfs.ChecksumType
+		blockSize        int64
+		bytesPerChecksum int32
+		want             webhdfs.FileChecksum
+	}{
+		{
+			// returned by GETFILECHECKSUM of a cluster, see TestClient_GetFileChecksum
+			name: "single block", data: []byte("Hello World!"),
+			mode: webhdfs.ChecksumCombineModeMD5MD5CRC, crcType: webhdfs.ChecksumTypeCRC32C, blockSize: 128 << 20, bytesPerChecksum: 512,
+			want: webhdfs.FileChecksum{Algorithm: "MD5-of-0MD5-of-512CRC32C", Bytes: "000002000000000000000000cbce76920e8bd8fea88009894bb094a5", Length: 28},
+		},
+		{
+			name: "multiple blocks", data: []byte("0123456789"),
+			mode: webhdfs.ChecksumCombineModeMD5MD5CRC, crcType: webhdfs.ChecksumTypeCRC32C, blockSize: 4, bytesPerChecksum: 2,
+			want: webhdfs.FileChecksum{Algorithm: "MD5-of-2MD5-of-2CRC32C", Bytes: "0000000200000000000000023ba6f91de42e94a26034ca1e5f04fbf9", Length: 28},
+		},
+		{
+			name: "multiple blocks of crc32", data: []byte("0123456789"),
+			mode: webhdfs.ChecksumCombineModeMD5MD5CRC, crcType: webhdfs.ChecksumTypeCRC32, blockSize: 4, bytesPerChecksum: 2,
+			want: webhdfs.FileChecksum{Algorithm: "MD5-of-2MD5-of-2CRC32", Bytes: "0000000200000000000000020866ad7c6e4d9bff2404f5194fb95c91", Length: 28},
+		},
+		{
+			// the MD5 of 3 blocks are padded to 64 bytes
+			name: "multiple blocks of partial chunks", data: bytes.Repeat(seq(256), 3),
+			mode: webhdfs.ChecksumCombineModeMD5MD5CRC, crcType: webhdfs.ChecksumTypeCRC32C, blockSize: 256, bytesPerChecksum: 100,
+			want: webhdfs.FileChecksum{Algorithm: "MD5-of-3MD5-of-100CRC32C", Bytes: "0000006400000000000000038f5207324b821579ed4acb3810ade71c", Length: 28},
+		},
+		{
+			// the check value of CRC-32C
+			name: "composite crc32c", data: []byte("123456789"),
+			mode: webhdfs.ChecksumCombineModeCompositeCRC, crcType: webhdfs.ChecksumTypeCRC32C, blockSize: 4, bytesPerChecksum: 2,
+			want: webhdfs.FileChecksum{Algorithm: "COMPOSITE-CRC32C", Bytes: "e3069283", Length: 4},
+		},
+		{
+			// the check value of CRC-32
+			name: "composite crc32", data: []byte("123456789"),
+			mode: webhdfs.ChecksumCombineModeCompositeCRC, crcType: webhdfs.ChecksumTypeCRC32, blockSize: 128 << 20, bytesPerChecksum: 512,
+			want: webhdfs.FileChecksum{Algorithm: "COMPOSITE-CRC32", Bytes: "cbf43926", Length: 4},
+		},
+	}
+	for _, tt := range tests {
+		d, err := webhdfs.ComputeFileChecksum(bytes.NewReader(tt.data), tt.mode, tt.crcType, tt.blockSize, tt.bytesPerChecksum)
+		if err != nil {
+			t.Errorf("%s: webhdfs ComputeFileChecksum failed: %s", tt.name, err)
+			continue
+		}
+		if got := d.Encode(); got != tt.want {
+			t.Errorf("%s: ComputeFileChecksum: got %+v, want %+v", tt.name, got, tt.want)
+		}
+		if d.Algorithm() != tt.want.Algorithm {
+			t.Errorf("%s: Algorithm: got %q, want %q", tt.name, d.Algorithm(), tt.want.Algorithm)
+		}
+	}
+}
+
+func TestFileChecksum_Decode(t *testing.T) {
+	tests := []struct {
+		checksum webhdfs.FileChecksum
+		want     webhdfs.DecodedFileChecksum
+	}{
+		{
+			// padded to 32 bytes by HDFS
+			checksum: webhdfs.FileChecksum{Algorithm: "MD5-of-0MD5-of-512CRC32C", Bytes: "000002000000000000000000cbce76920e8bd8fea88009894bb094a500000000", Length: 28},
+			want: webhdfs.DecodedFileChecksum{
+				CombineMode: webhdfs.ChecksumCombineModeMD5MD5CRC, CrcType: webhdfs.ChecksumTypeCRC32C, BytesPerCRC: 512,
+				MD5: [16]byte{0xcb, 0xce, 0x76, 0x92, 0x0e, 0x8b, 0xd8, 0xfe, 0xa8, 0x80, 0x09, 0x89, 0x4b, 0xb0, 0x94, 0xa5},
+			},
+		},
+		{
+			checksum: webhdfs.FileChecksum{Algorithm: "MD5-of-2MD5-of-2CRC32", Bytes: "0000000200000000000000020866AD7C6E4D9BFF2404F5194FB95C91", Length: 28},
+			want: webhdfs.DecodedFileChecksum{
+				CombineMode: webhdfs.ChecksumCombineModeMD5MD5CRC, CrcType: webhdfs.ChecksumTypeCRC32, BytesPerCRC: 2, CRCPerBlock: 2,
+				MD5: [16]byte{0x08, 0x66, 0xad, 0x7c, 0x6e, 0x4d, 0x9b, 0xff, 0x24, 0x04, 0xf5, 0x19, 0x4f, 0xb9, 0x5c, 0x91},
+			},
+		},
+		{
+			checksum: webhdfs.FileChecksum{Algorithm: "COMPOSITE-CRC32C", Bytes: "e3069283", Length: 4},
+			want:     webhdfs.DecodedFileChecksum{CombineMode: webhdfs.ChecksumCombineModeCompositeCRC, CrcType: webhdfs.ChecksumTypeCRC32C, CRC: 0xe3069283},
+		},
+	}
+	for _, tt := range tests {
+		d, err := tt.checksum.Decode()
+		if err != nil {
+			t.Errorf("%s: webhdfs Decode failed: %s", tt.checksum.Algorithm, err)
+			continue
+		}
+		if d != tt.want {
+			t.Errorf("%s: Decode: got %+v, want %+v", tt.checksum.Algorithm, d, tt.want)
+		}
+		// Encode(Decode(x)) == x, but the zeros padded and the case of the bytes
+		got := d.Encode()
+		if got.Algorithm != tt.checksum.Algorithm || got.Length != tt.checksum.Length ||
+			!strings.EqualFold(got.Bytes, tt.checksum.Bytes[:2*tt.checksum.Length]) {
+			t.Errorf("%s: Encode: got %+v, want %+v", tt.checksum.Algorithm, got, tt.checksum)
+		}
+	}
+}
+
+func TestFileChecksum_Decode_Invalid(t *testing.T) {
+	for _, checksum := range []webhdfs.FileChecksum{
+		{Algorithm: "MD5-of-0MD5-of-512CRC32C", Bytes: "000002000000000000000000cbce7692", Length: 16},
+		{Algorithm: "MD5-of-1MD5-of-512CRC32C", Bytes: "000002000000000000000000cbce76920e8bd8fea88009894bb094a5", Length: 28},
+		{Algorithm: "MD5-of-0MD5-of-512CRC64", Bytes: "000002000000000000000000cbce76920e8bd8fea88009894bb094a5", Length: 28},
+		{Algorithm: "MD5-of-0MD5-of-512CRC32C", Bytes: "not hex", Length: 28},
+		{Algorithm: "COMPOSITE-CRC32C", Bytes: "e306928300", Length: 5},
+		{Algorithm: "COMPOSITE-MD5", Bytes: "e3069283", Length: 4},
+		{Algorithm: "SHA-256", Bytes: "e3069283", Length: 4},
+	} {
+		if d, err := checksum.Decode(); err == nil {
+			t.Errorf("Decode(%+v): got %+v, want error", checksum, d)
+		}
+	}
+}
+
+// seq returns the bytes 0, 1, ..., n-1.
+func seq(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
@@ -6,15 +6,22 @@ package webhdfs_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/searKing/golang/go/exp/types"
@@ -863,6 +870,59 @@ func TestClient_GetFileChecksum_File_NotFound(t *testing.T) {
 	//    client_test.go:851: webhdfs GetFileChecksum failed: FileNotFoundException: File does not exist: /test.bucket/test/notfound.txt in java.io.FileNotFoundException
 }
 
+func TestClient_ComputeFileChecksum(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	writtenData := "Hello World!"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+	var blockSize int64
+	func() {
+		resp, err := c.GetFileStatus(&webhdfs.GetFileStatusRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs GetFileStatus failed: %s", err)
+		}
+		defer resp.Body.Close()
+		blockSize = resp.FileStatus.BlockSize
+	}()
+	resp, err := c.GetFileChecksum(&webhdfs.GetFileChecksumRequest{
+		ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+		Path:      types.Pointer(file),
+	})
+	if err != nil {
+		t.Fatalf("webhdfs GetFileChecksum failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	expected, err := resp.FileChecksum.Decode()
+	if err != nil {
+		t.Fatalf("webhdfs FileChecksum Decode failed: %s", err)
+	}
+	t.Logf("FileChecksum: %+v", expected)
+	actual, err := webhdfs.ComputeFileChecksum(strings.NewReader(writtenData),
+		expected.CombineMode, expected.CrcType, blockSize, expected.BytesPerCRC)
+	if err != nil {
+		t.Fatalf("webhdfs ComputeFileChecksum failed: %s", err)
+	}
+	if actual != expected {
+		t.Errorf("FileChecksum: got %+v, want %+v", actual, expected)
+	}
+}
+
 func TestClient_GetHomeDirectory(t *testing.T) {
 	c := getWebHDFSClient(t)
 
@@ -949,6 +1009,92 @@ func TestClient_GetXAttr(t *testing.T) {
 	//    client_test.go:939: XAttrs: [{user.name "example"}]
 }
 
+func TestClient_GetXAttr_Encoding(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	writtenData := "Hello World!"
+	XAttrName := webhdfs.XAttrNamespaceUser.String() + ".name"
+	XAttrValue := "example"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.SetXAttr(&webhdfs.SetXAttrRequest{
+			ProxyUser:  c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:       types.Pointer(file),
+			XAttrName:  types.Pointer(XAttrName),
+			XAttrValue: types.Pointer(XAttrValue),
+			XAttrFlag:  webhdfs.XAttrSetFlagCreate.New(),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs SetXAttr failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	for _, tt := range []struct {
+		encoding webhdfs.XAttrValueEncoding
+		want     string
+	}{
+		{webhdfs.XAttrValueEncodingText, `"example"`},
+		{webhdfs.XAttrValueEncodingHex, "0x6578616d706c65"},
+		{webhdfs.XAttrValueEncodingBase64, "0sZXhhbXBsZQ=="},
+	} {
+		func() {
+			resp, err := c.GetXAttr(&webhdfs.GetXAttrRequest{
+				ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+				Path:      types.Pointer(file),
+				XAttrName: types.Pointer(XAttrName),
+				Encoding:  tt.encoding.New(),
+			})
+			if err != nil {
+				t.Fatalf("webhdfs GetXAttr of encoding %s failed: %s", tt.encoding, err)
+			}
+			defer resp.Body.Close()
+			t.Logf("XAttrs of encoding %s: %v", tt.encoding, resp.XAttrs)
+
+			if len(resp.XAttrs) != 1 {
+				t.Fatalf("len(XAttrs) of encoding %s: got %d, want %d", tt.encoding, len(resp.XAttrs), 1)
+			}
+			if xattr := resp.XAttrs[0]; xattr.Value != tt.want {
+				t.Errorf("XAttrValue of encoding %s: got %s, want %s", tt.encoding, xattr.Value, tt.want)
+			}
+		}()
+		func() {
+			resp, err := c.GetXAttrs(&webhdfs.GetXAttrsRequest{
+				ProxyUser:  c.ProxyUser(), // optional, user.name, The authenticated user
+				Path:       types.Pointer(file),
+				XAttrNames: []string{XAttrName},
+				Encoding:   tt.encoding.New(),
+			})
+			if err != nil {
+				t.Fatalf("webhdfs GetXAttrs of encoding %s failed: %s", tt.encoding, err)
+			}
+			defer resp.Body.Close()
+		}()
+		func() {
+			resp, err := c.GetAllXAttrs(&webhdfs.GetAllXAttrsRequest{
+				ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+				Path:      types.Pointer(file),
+				Encoding:  tt.encoding.New(),
+			})
+			if err != nil {
+				t.Fatalf("webhdfs GetAllXAttrs of encoding %s failed: %s", tt.encoding, err)
+			}
+			defer resp.Body.Close()
+		}()
+	}
+}
+
 func TestClient_GetXAttrs(t *testing.T) {
 	c := getWebHDFSClient(t)
 	file := HdfsBucket + "/test/found.txt"
@@ -1143,7 +1289,7 @@ func TestClient_CheckAccess(t *testing.T) {
 		resp, err := c.CheckAccess(&webhdfs.CheckAccessRequest{
 			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
 			Path:      types.Pointer(file),
-			Fsaction:  types.Pointer("rwx"),
+			Fsaction:  webhdfs.FsActionAll.New(),
 		})
 		if err != nil {
 			t.Fatalf("webhdfs CheckAccess failed: %s", err)
@@ -1154,6 +1300,59 @@ func TestClient_CheckAccess(t *testing.T) {
 	//    client_test.go:1151: webhdfs CheckAccess failed: QueryParamException: java.lang.IllegalArgumentException: No enum constant org.apache.hadoop.fs.http.client.HttpFSFileSystem.Operation.CHECKACCESS in com.sun.jersey.api.ParamException$QueryParamException
 }
 
+func TestClient_AccessChecker(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	writtenData := "Hello World!"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+
+	var node webhdfs.AccessNode
+	func() {
+		resp, err := c.GetFileStatus(&webhdfs.GetFileStatusRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs GetFileStatus failed: %s", err)
+		}
+		defer resp.Body.Close()
+		node.Status = resp.FileStatus
+	}()
+	func() {
+		resp, err := c.GetAclStatus(&webhdfs.GetAclStatusRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs GetAclStatus failed: %s", err)
+		}
+		defer resp.Body.Close()
+		node.Acl = resp.AclStatus.Entries
+	}()
+
+	checker := webhdfs.AccessChecker{User: node.Status.Owner}
+	d, err := checker.Check([]webhdfs.AccessNode{node}, webhdfs.FsActionReadWrite)
+	if err != nil {
+		t.Fatalf("webhdfs AccessChecker Check failed: %s", err)
+	}
+	t.Logf("%s", d)
+	if !d.Allowed || d.Rule != webhdfs.AccessRuleOwner {
+		t.Errorf("AccessDecision: got %s, want allowed as %s", d, webhdfs.AccessRuleOwner)
+	}
+}
+
 func TestClient_GetAllStoragePolicy(t *testing.T) {
 	c := getWebHDFSClient(t)
 	resp, err := c.GetAllStoragePolicy(&webhdfs.GetAllStoragePolicyRequest{
@@ -1618,6 +1817,46 @@ func TestClient_GetFileBlockLocations(t *testing.T) {
 	// client_test.go:273: webhdfs GetAllStoragePolicy failed: IllegalArgumentException: Invalid value for webhdfs parameter "op": No enum constant org.apache.hadoop.hdfs.web.resources.GetOpParam.Op.GETFILEBLOCKLOCATIONS in java.lang.IllegalArgumentException
 }
 
+func TestClient_GetFileBlockLocations_Range(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	writtenData := "Hello World!"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+
+	resp, err := c.GetFileBlockLocations(&webhdfs.GetFileBlockLocationsRequest{
+		ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+		Path:      types.Pointer(file),
+		Offset:    types.Pointer(int64(6)),
+		Length:    types.Pointer(int64(5)),
+	})
+	if err != nil {
+		t.Fatalf("webhdfs GetFileBlockLocations failed: %s", err)
+	}
+	defer resp.Body.Close()
+	blocks := resp.BlockLocations.Range(6, 5)
+	if len(blocks) != 1 {
+		t.Fatalf("BlockLocations of [6, 11): got %d blocks, want %d", len(blocks), 1)
+	}
+	for _, block := range blocks {
+		t.Logf("BlockLocation: [%d, %d), best host %q", block.Offset, block.End(), block.BestHost("", ""))
+	}
+	if corrupt := resp.BlockLocations.CorruptBlocks(); len(corrupt) > 0 {
+		t.Errorf("BlockLocations: got %d corrupt blocks, want none", len(corrupt))
+	}
+}
+
 func TestClient_GetECPolicy(t *testing.T) {
 	c := getWebHDFSClient(t)
 	file := HdfsBucket + "/test/found.txt"
@@ -1653,6 +1892,78 @@ func TestClient_GetECPolicy(t *testing.T) {
 	//    client_test.go:1629: webhdfs GetECPolicy failed: QueryParamException: java.lang.IllegalArgumentException: No enum constant org.apache.hadoop.fs.http.client.HttpFSFileSystem.Operation.GETECPOLICY in com.sun.jersey.api.ParamException$QueryParamException
 }
 
+func TestClient_GetServerDefaults(t *testing.T) {
+	c := getWebHDFSClient(t)
+
+	resp, err := c.GetServerDefaults(&webhdfs.GetServerDefaultsRequest{
+		ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+	})
+	if err != nil {
+		t.Fatalf("webhdfs GetServerDefaults failed: %s", err)
+	}
+	defer resp.Body.Close()
+	t.Logf("FsServerDefaults: %+v", resp.FsServerDefaults)
+	if resp.FsServerDefaults.BlockSize <= 0 {
+		t.Errorf("BlockSize: got %d, want > 0", resp.FsServerDefaults.BlockSize)
+	}
+}
+
+func TestClient_GetStatus(t *testing.T) {
+	c := getWebHDFSClient(t)
+
+	resp, err := c.GetStatus(&webhdfs.GetStatusRequest{
+		ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+		Path:      types.Pointer(HdfsBucket),
+	})
+	if err != nil {
+		t.Fatalf("webhdfs GetStatus failed: %s", err)
+	}
+	defer resp.Body.Close()
+	t.Logf("FsStatus: %+v", resp.FsStatus)
+	if resp.FsStatus.Used+resp.FsStatus.Remaining > resp.FsStatus.Capacity {
+		t.Errorf("FsStatus: used %d and remaining %d exceed capacity %d",
+			resp.FsStatus.Used, resp.FsStatus.Remaining, resp.FsStatus.Capacity)
+	}
+}
+
+func TestClient_GetSnapshotList(t *testing.T) {
+	c := getWebHDFSClient(t)
+	dir := HdfsBucket + "/test/snapshot"
+	func() {
+		resp, err := c.Mkdirs(&webhdfs.MkdirsRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(dir),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Mkdirs failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.AllowSnapshot(&webhdfs.AllowSnapshotRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(dir),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs AllowSnapshot failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.GetSnapshotList(&webhdfs.GetSnapshotListRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(dir),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs GetSnapshotList failed: %s", err)
+		}
+		defer resp.Body.Close()
+		for _, s := range resp.SnapshotList {
+			t.Logf("Snapshot: %d %s %s", s.SnapshotID, s.FullPath, s.DeletionStatus)
+		}
+	}()
+}
+
 func TestClient_Create_File_Overwrite(t *testing.T) {
 	c := getWebHDFSClient(t)
 	file := HdfsBucket + "/test/found.txt"
@@ -1767,6 +2078,29 @@ func TestClient_Create_File_AlreadyExist(t *testing.T) {
 	//    client_test.go:1743: webhdfs Create failed: FileAlreadyExistsException: /test.bucket/test/found.txt for client 10.22.0.30 already exists in org.apache.hadoop.fs.FileAlreadyExistsException
 }
 
+func TestClient_Create_File_NoCreateParent(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/notfound/found.txt"
+	writtenData := "Hello World!"
+	resp, err := c.Create(&webhdfs.CreateRequest{
+		ProxyUser:    c.ProxyUser(), // optional, user.name, The authenticated user
+		Path:         types.Pointer(file),
+		Body:         strings.NewReader(writtenData),
+		Overwrite:    types.Pointer(true),
+		CreateParent: types.Pointer(false),
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			t.Logf("webhdfs Create failed: %s", err)
+		} else {
+			t.Fatalf("webhdfs Create failed: %s", err)
+		}
+		return
+	}
+	defer resp.Body.Close()
+	t.Fatalf("webhdfs Create succeed unexpected for not exist parent directory")
+}
+
 func TestClient_Create_Dir(t *testing.T) {
 	c := getWebHDFSClient(t)
 	file := HdfsBucket + "/test.create_dir/"
@@ -2247,22 +2581,10 @@ func TestClient_SetPermission(t *testing.T) {
 	//    client_test.go:2221: [0] Dir(false), found.txt, 12, 2021-01-29 20:15:57.264 +0800 CST, 0666
 }
 
-func TestClient_SetTimes(t *testing.T) {
+func TestClient_SetPermission_Symbolic(t *testing.T) {
 	c := getWebHDFSClient(t)
 	file := HdfsBucket + "/test/found.txt"
 	writtenData := "Hello World!"
-	now := time.Now()
-	func() {
-		resp, err := c.Delete(&webhdfs.DeleteRequest{
-			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
-			Path:      types.Pointer(file),
-		})
-		if err != nil {
-			t.Fatalf("webhdfs Delete failed: %s", err)
-			return
-		}
-		defer resp.Body.Close()
-	}()
 	func() {
 		resp, err := c.Create(&webhdfs.CreateRequest{
 			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
@@ -2276,24 +2598,99 @@ func TestClient_SetTimes(t *testing.T) {
 		}
 		defer resp.Body.Close()
 	}()
+	var perm webhdfs.Permission
 	func() {
-		resp, err := c.SetTimes(&webhdfs.SetTimesRequest{
+		resp, err := c.GetFileStatus(&webhdfs.GetFileStatusRequest{
 			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
 			Path:      types.Pointer(file),
-			Modificationtime: &time_.UnixTimeMillisecond{
-				Time: now,
-			},
 		})
 		if err != nil {
-			t.Fatalf("webhdfs SetTimes failed: %s", err)
+			t.Fatalf("webhdfs GetFileStatus failed: %s", err)
 		}
 		defer resp.Body.Close()
+		perm, err = resp.FileStatus.Permission.Chmod("u+x,g-r,o=w")
+		if err != nil {
+			t.Fatalf("webhdfs Permission Chmod failed: %s", err)
+		}
 	}()
 	func() {
-		resp, err := c.ListStatus(&webhdfs.ListStatusRequest{
-			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
-			Path:      types.Pointer(file),
-		})
+		resp, err := c.SetPermission(&webhdfs.SetPermissionRequest{
+			ProxyUser:  c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:       types.Pointer(file),
+			Permission: perm.New(),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs SetPermission failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.GetFileStatus(&webhdfs.GetFileStatusRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs GetFileStatus failed: %s", err)
+		}
+		defer resp.Body.Close()
+		fi := resp.FileStatus
+		t.Logf("%s, %s, %s", fi.Name(), fi.Permission.Symbolic(), fi.Mode())
+		if fi.Permission.Symbolic() != perm.Symbolic() {
+			t.Errorf("Permission: got %s, want %s", fi.Permission.Symbolic(), perm.Symbolic())
+		}
+		if fi.Permission.User() != webhdfs.FsActionAll {
+			t.Errorf("Permission User: got %s, want %s", fi.Permission.User(), webhdfs.FsActionAll)
+		}
+	}()
+}
+
+func TestClient_SetTimes(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	writtenData := "Hello World!"
+	now := time.Now()
+	func() {
+		resp, err := c.Delete(&webhdfs.DeleteRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Delete failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.SetTimes(&webhdfs.SetTimesRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Modificationtime: &time_.UnixTimeMillisecond{
+				Time: now,
+			},
+		})
+		if err != nil {
+			t.Fatalf("webhdfs SetTimes failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.ListStatus(&webhdfs.ListStatusRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+		})
 		if err != nil {
 			t.Fatalf("webhdfs ListStatus failed: %s", err)
 		}
@@ -2794,6 +3191,97 @@ func TestClient_RemoveXAttr(t *testing.T) {
 	//    client_test.go:2772: webhdfs RemoveXAttr failed: RemoteException: No matching attributes found for remove operation in org.apache.hadoop.ipc.RemoteException
 }
 
+func TestClient_SetAcl(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	writtenData := "Hello World!"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+	aclSpec, err := webhdfs.ParseAclSpec("user::rw-,user:alice:rw-,group::r--,mask::rw-,other::---", true)
+	if err != nil {
+		t.Fatalf("webhdfs ParseAclSpec failed: %s", err)
+	}
+	func() {
+		resp, err := c.SetAcl(&webhdfs.SetAclRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			AclSpec:   aclSpec,
+		})
+		if err != nil {
+			t.Fatalf("webhdfs SetAcl failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.ModifyAclEntries(&webhdfs.ModifyAclEntriesRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			AclSpec: []webhdfs.AclEntry{
+				{Type: webhdfs.AclEntryTypeUser, Name: "bob", Permission: webhdfs.FsActionRead},
+			},
+		})
+		if err != nil {
+			t.Fatalf("webhdfs ModifyAclEntries failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.RemoveAclEntries(&webhdfs.RemoveAclEntriesRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			AclSpec: []webhdfs.AclEntry{
+				{Type: webhdfs.AclEntryTypeUser, Name: "alice"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("webhdfs RemoveAclEntries failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.GetAclStatus(&webhdfs.GetAclStatusRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs GetAclStatus failed: %s", err)
+		}
+		defer resp.Body.Close()
+		t.Logf("AclStatus: %v", resp.AclStatus)
+
+		var names []string
+		for _, e := range resp.AclStatus.Entries {
+			if e.Type == webhdfs.AclEntryTypeUser {
+				names = append(names, e.Name)
+			}
+		}
+		if len(names) != 1 || names[0] != "bob" {
+			t.Errorf("AclStatus named users: got %v, want %v", names, []string{"bob"})
+		}
+	}()
+	func() {
+		resp, err := c.RemoveAcl(&webhdfs.RemoveAclRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs RemoveAcl failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+}
+
 func TestClient_SetStoragePolicy(t *testing.T) {
 	c := getWebHDFSClient(t)
 	file := HdfsBucket + "/test/found.txt"
@@ -3469,6 +3957,62 @@ func TestClient_Truncate(t *testing.T) {
 	//    client_test.go:3461: test.bucket/test/found.txt, read ""
 }
 
+func TestClient_TruncateAndWait(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	writtenData := "Hello World!"
+	newLength := len(writtenData) / 2
+	appendData := "Hello Again!"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := c.TruncateAndWait(ctx, file, int64(newLength)); err != nil {
+		t.Fatalf("webhdfs TruncateAndWait failed: %s", err)
+	}
+	func() {
+		resp, err := c.Append(&webhdfs.AppendRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(appendData),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Append failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.Open(&webhdfs.OpenRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Open failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+		readData, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("webhdfs Open and Read failed: %s", err)
+		}
+		if expected := writtenData[:newLength] + appendData; expected != string(readData) {
+			t.Fatalf("%s, expected %q, got %q", file, expected, readData)
+		}
+	}()
+}
+
 func TestClient_UnsetStoragePolicy(t *testing.T) {
 	c := getWebHDFSClient(t)
 	file := HdfsBucket + "/test/found.txt"
@@ -3672,6 +4216,41 @@ func TestClient_Delete(t *testing.T) {
 	//    client_test.go:3664: webhdfs Open failed: FileNotFoundException: File does not exist: /test.bucket/test/found.txt in java.io.FileNotFoundException
 }
 
+func TestClient_Delete_Boolean(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	writtenData := "Hello World!"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+	for _, want := range []bool{true, false} { // existing, then missing as deleted
+		func() {
+			resp, err := c.Delete(&webhdfs.DeleteRequest{
+				ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+				Path:      types.Pointer(file),
+			})
+			if err != nil {
+				t.Fatalf("webhdfs Delete failed: %s", err)
+				return
+			}
+			defer resp.Body.Close()
+			if resp.Boolean != want {
+				t.Errorf("Boolean: got %t, want %t", resp.Boolean, want)
+			}
+		}()
+	}
+}
+
 func TestClient_DeleteSnapshot(t *testing.T) {
 	c := getWebHDFSClient(t)
 	dir := HdfsBucket + "/test"
@@ -3731,3 +4310,974 @@ func TestClient_DeleteSnapshot(t *testing.T) {
 		}()
 	}()
 }
+
+func TestFS(t *testing.T) {
+	c := getWebHDFSClient(t)
+	dir := HdfsBucket + "/test"
+	files := map[string]string{
+		"found.txt":        "Hello World!",
+		"subdir/found.txt": "Hello World!",
+	}
+	for name, data := range files {
+		func() {
+			resp, err := c.Create(&webhdfs.CreateRequest{
+				ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+				Path:      types.Pointer(path.Join(dir, name)),
+				Body:      strings.NewReader(data),
+				Overwrite: types.Pointer(true),
+			})
+			if err != nil {
+				t.Fatalf("webhdfs Create failed: %s", err)
+				return
+			}
+			defer resp.Body.Close()
+		}()
+	}
+
+	fsys := webhdfs.NewFS(c, dir)
+	if err := fstest.TestFS(fsys, "found.txt", "subdir/found.txt"); err != nil {
+		t.Fatalf("webhdfs FS failed: %s", err)
+	}
+
+	for name, data := range files {
+		readData, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			t.Fatalf("webhdfs FS ReadFile failed: %s", err)
+		}
+		if string(readData) != data {
+			t.Errorf("%s, expected %q, got %q", name, data, readData)
+		}
+	}
+
+	_, err := fs.Stat(fsys, "notfound.txt")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("webhdfs FS Stat: expected %v, got %v", fs.ErrNotExist, err)
+	}
+}
+
+func TestFS_WithContext(t *testing.T) {
+	c := getWebHDFSClient(t)
+	fsys := webhdfs.NewFS(c, HdfsBucket)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fs.Stat(fsys.WithContext(ctx), "."); !errors.Is(err, context.Canceled) {
+		t.Errorf("webhdfs FS Stat: expected %v, got %v", context.Canceled, err)
+	}
+	if _, err := fs.ReadDir(fsys.WithContext(ctx), "."); !errors.Is(err, context.Canceled) {
+		t.Errorf("webhdfs FS ReadDir: expected %v, got %v", context.Canceled, err)
+	}
+	if _, err := fs.Stat(fsys, "."); err != nil {
+		t.Fatalf("webhdfs FS Stat failed: %s", err)
+	}
+}
+
+func TestFS_Write(t *testing.T) {
+	c := getWebHDFSClient(t)
+	fsys := webhdfs.NewFS(c, HdfsBucket+"/test")
+	name := "fs/found.txt"
+	writtenData := "Hello World!"
+
+	if err := fsys.RemoveAll("fs"); err != nil {
+		t.Fatalf("webhdfs FS RemoveAll failed: %s", err)
+	}
+	if err := fsys.MkdirAll("fs", 0755); err != nil {
+		t.Fatalf("webhdfs FS MkdirAll failed: %s", err)
+	}
+	func() {
+		f, err := fsys.Create(name)
+		if err != nil {
+			t.Fatalf("webhdfs FS Create failed: %s", err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(writtenData); err != nil {
+			t.Fatalf("webhdfs FS Write failed: %s", err)
+		}
+	}()
+	if _, err := fsys.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("webhdfs FS OpenFile O_EXCL: expected %v, got %v", fs.ErrExist, err)
+	}
+	func() {
+		f, err := fsys.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			t.Fatalf("webhdfs FS OpenFile O_APPEND failed: %s", err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(writtenData); err != nil {
+			t.Fatalf("webhdfs FS Write failed: %s", err)
+		}
+	}()
+	readData, err := fsys.ReadFile(name)
+	if err != nil {
+		t.Fatalf("webhdfs FS ReadFile failed: %s", err)
+	}
+	if string(readData) != writtenData+writtenData {
+		t.Errorf("%s, expected %q, got %q", name, writtenData+writtenData, readData)
+	}
+
+	if err := fsys.Chmod(name, 0600); err != nil {
+		t.Fatalf("webhdfs FS Chmod failed: %s", err)
+	}
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	if err := fsys.Chtimes(name, time.Time{}, mtime); err != nil {
+		t.Fatalf("webhdfs FS Chtimes failed: %s", err)
+	}
+	fi, err := fsys.Stat(name)
+	if err != nil {
+		t.Fatalf("webhdfs FS Stat failed: %s", err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("Mode(): got %s, want %s", fi.Mode().Perm(), fs.FileMode(0600))
+	}
+	if !fi.ModTime().Equal(mtime) {
+		t.Errorf("ModTime(): got %s, want %s", fi.ModTime(), mtime)
+	}
+
+	renamed := "fs/renamed.txt"
+	if err := fsys.Rename(name, renamed); err != nil {
+		t.Fatalf("webhdfs FS Rename failed: %s", err)
+	}
+	if err := fsys.Remove(name); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("webhdfs FS Remove: expected %v, got %v", fs.ErrNotExist, err)
+	}
+	if err := fsys.Remove(renamed); err != nil {
+		t.Fatalf("webhdfs FS Remove failed: %s", err)
+	}
+}
+
+func TestFile_ReadSeek(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := "test/found.txt"
+	writtenData := "Hello World!"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(path.Join(HdfsBucket, file)),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+
+	f, err := webhdfs.NewFS(c, HdfsBucket).OpenFile(file, os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatalf("webhdfs FS OpenFile failed: %s", err)
+	}
+	defer f.Close()
+
+	// footer
+	footer := make([]byte, 6)
+	if _, err := f.ReadAt(footer, int64(len(writtenData)-len(footer))); err != nil {
+		t.Fatalf("webhdfs File ReadAt failed: %s", err)
+	}
+	if string(footer) != writtenData[len(writtenData)-len(footer):] {
+		t.Errorf("ReadAt: got %q, want %q", footer, writtenData[len(writtenData)-len(footer):])
+	}
+
+	if _, err := f.Seek(6, io.SeekStart); err != nil {
+		t.Fatalf("webhdfs File Seek failed: %s", err)
+	}
+	readData, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("webhdfs File Read failed: %s", err)
+	}
+	if string(readData) != writtenData[6:] {
+		t.Errorf("Read: got %q, want %q", readData, writtenData[6:])
+	}
+	t.Logf("%s, written %q, footer %q, read from 6 %q", file, writtenData, footer, readData)
+}
+
+func TestClient_NewWriter(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/writer.txt"
+	writtenData := strings.Repeat("Hello World!", 100)
+
+	w := c.NewWriter(context.Background(), file, webhdfs.WithWriterOverwrite(true), webhdfs.WithWriterChunkSize(500))
+	if _, err := io.Copy(w, strings.NewReader(writtenData)); err != nil {
+		t.Fatalf("webhdfs Writer Write failed: %s", err)
+	}
+	n, err := w.Finish()
+	if err != nil {
+		t.Fatalf("webhdfs Writer Finish failed: %s", err)
+	}
+	if n != int64(len(writtenData)) {
+		t.Errorf("Finish(): got %d, want %d", n, len(writtenData))
+	}
+
+	resp, err := c.Open(&webhdfs.OpenRequest{
+		ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+		Path:      types.Pointer(file),
+	})
+	if err != nil {
+		t.Fatalf("webhdfs Open failed: %s", err)
+		return
+	}
+	defer resp.Body.Close()
+	readData, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("webhdfs Open and Read failed: %s", err)
+	}
+	if string(readData) != writtenData {
+		t.Fatalf("%s, expected %q, got %q", file, writtenData, readData)
+	}
+}
+
+func TestClient_Download(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/download.txt"
+	writtenData := strings.Repeat("Hello World!", 1000)
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+
+	f, err := ioutil.TempFile("", "download")
+	if err != nil {
+		t.Fatalf("create temp file failed: %s", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	n, err := c.Download(context.Background(), file, f,
+		webhdfs.WithDownloaderConcurrency(3),
+		webhdfs.WithDownloaderProgress(func(downloaded, total int64) {
+			t.Logf("downloaded %d/%d", downloaded, total)
+		}))
+	if err != nil {
+		t.Fatalf("webhdfs Download failed: %s", err)
+	}
+	if n != int64(len(writtenData)) {
+		t.Errorf("Download: got %d bytes, want %d", n, len(writtenData))
+	}
+	readData, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("read temp file failed: %s", err)
+	}
+	if string(readData) != writtenData {
+		t.Fatalf("%s, expected %q, got %q", file, writtenData, readData)
+	}
+}
+
+func TestClient_Upload(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/upload.txt"
+	writtenData := strings.Repeat("Hello World!", 1<<20)
+
+	n, err := c.Upload(context.Background(), file, strings.NewReader(writtenData),
+		webhdfs.WithUploaderOverwrite(true),
+		webhdfs.WithUploaderBlocksize(1<<20),
+		webhdfs.WithUploaderConcurrency(3),
+		webhdfs.WithUploaderProgress(func(uploaded int64) {
+			t.Logf("uploaded %d", uploaded)
+		}))
+	if err != nil {
+		t.Fatalf("webhdfs Upload failed: %s", err)
+	}
+	if n != int64(len(writtenData)) {
+		t.Errorf("Upload: got %d bytes, want %d", n, len(writtenData))
+	}
+
+	readData, err := webhdfs.NewFS(c, HdfsBucket).ReadFile("test/upload.txt")
+	if err != nil {
+		t.Fatalf("webhdfs ReadFile failed: %s", err)
+	}
+	if string(readData) != writtenData {
+		t.Fatalf("%s, content mismatch, got %d bytes", file, len(readData))
+	}
+}
+
+func TestClient_ResumeUpload(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/resume_upload.txt"
+	writtenData := strings.Repeat("Hello World!", 1000)
+
+	dir, err := ioutil.TempDir("", "resume_upload")
+	if err != nil {
+		t.Fatalf("create temp dir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	local := filepath.Join(dir, "upload.txt")
+	if err := ioutil.WriteFile(local, []byte(writtenData), 0644); err != nil {
+		t.Fatalf("write local file failed: %s", err)
+	}
+	checkpoint := local + ".checkpoint"
+
+	n, err := c.ResumeUpload(context.Background(), local, file, checkpoint,
+		webhdfs.WithWriterOverwrite(true), webhdfs.WithWriterChunkSize(4096))
+	if err != nil {
+		t.Fatalf("webhdfs ResumeUpload failed: %s", err)
+	}
+	if n != int64(len(writtenData)) {
+		t.Errorf("ResumeUpload: got %d bytes, want %d", n, len(writtenData))
+	}
+	if _, err := os.Stat(checkpoint); !os.IsNotExist(err) {
+		t.Errorf("checkpoint %s should be removed, got %v", checkpoint, err)
+	}
+
+	readData, err := webhdfs.NewFS(c, HdfsBucket).ReadFile("test/resume_upload.txt")
+	if err != nil {
+		t.Fatalf("webhdfs ReadFile failed: %s", err)
+	}
+	if string(readData) != writtenData {
+		t.Fatalf("%s, content mismatch, got %d bytes", file, len(readData))
+	}
+}
+
+func TestClient_ResumeDownload(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/resume_download.txt"
+	writtenData := strings.Repeat("Hello World!", 1000)
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader(writtenData),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+			return
+		}
+		defer resp.Body.Close()
+	}()
+
+	dir, err := ioutil.TempDir("", "resume_download")
+	if err != nil {
+		t.Fatalf("create temp dir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	local := filepath.Join(dir, "download.txt")
+	// a partial download left behind
+	if err := ioutil.WriteFile(local, []byte(writtenData[:100]), 0644); err != nil {
+		t.Fatalf("write local file failed: %s", err)
+	}
+
+	n, err := c.ResumeDownload(context.Background(), file, local)
+	if err != nil {
+		var mismatch *webhdfs.ChecksumMismatchError
+		if errors.As(err, &mismatch) {
+			t.Fatalf("webhdfs ResumeDownload checksum mismatch, expected %s, actual %s", mismatch.Expected.Bytes, mismatch.Actual.Bytes)
+		}
+		t.Fatalf("webhdfs ResumeDownload failed: %s", err)
+	}
+	if n != int64(len(writtenData)) {
+		t.Errorf("ResumeDownload: got %d bytes, want %d", n, len(writtenData))
+	}
+	readData, err := ioutil.ReadFile(local)
+	if err != nil {
+		t.Fatalf("read local file failed: %s", err)
+	}
+	if string(readData) != writtenData {
+		t.Fatalf("%s, content mismatch, got %d bytes", file, len(readData))
+	}
+}
+
+func TestClient_Walk(t *testing.T) {
+	c := getWebHDFSClient(t)
+	dir := HdfsBucket + "/test/walk"
+	fsys := webhdfs.NewFS(c, HdfsBucket)
+	for _, name := range []string{"test/walk/a/1.txt", "test/walk/a/2.txt", "test/walk/b/3.txt", "test/walk/c.txt"} {
+		if err := fsys.MkdirAll(path.Dir(name), 0755); err != nil {
+			t.Fatalf("webhdfs MkdirAll failed: %s", err)
+		}
+		f, err := fsys.Create(name)
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+		}
+		f.Close()
+	}
+
+	var walked []string
+	err := c.Walk(context.Background(), dir, func(p string, info *webhdfs.FileStatus, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, strings.TrimPrefix(p, dir))
+		if info.IsDir() && info.Name() == "b" {
+			return webhdfs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("webhdfs Walk failed: %s", err)
+	}
+	want := []string{"", "/a", "/a/1.txt", "/a/2.txt", "/b", "/c.txt"}
+	if strings.Join(walked, ",") != strings.Join(want, ",") {
+		t.Errorf("Walk: got %q, want %q", walked, want)
+	}
+}
+
+func TestClient_Crawl(t *testing.T) {
+	c := getWebHDFSClient(t)
+	dir := HdfsBucket + "/test/crawl"
+	fsys := webhdfs.NewFS(c, HdfsBucket)
+	for _, name := range []string{"test/crawl/a/1.txt", "test/crawl/a/b/2.txt", "test/crawl/c.txt", "test/crawl/tmp/3.txt"} {
+		if err := fsys.MkdirAll(path.Dir(name), 0755); err != nil {
+			t.Fatalf("webhdfs MkdirAll failed: %s", err)
+		}
+		f, err := fsys.Create(name)
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+		}
+		f.Close()
+	}
+
+	var mu sync.Mutex
+	var crawled []string
+	err := c.Crawl(context.Background(), dir, func(p string, info *webhdfs.FileStatus) error {
+		mu.Lock()
+		defer mu.Unlock()
+		crawled = append(crawled, strings.TrimPrefix(p, dir))
+		return nil
+	}, webhdfs.WithCrawlerConcurrency(4),
+		webhdfs.WithCrawlerInclude(func(p string, info *webhdfs.FileStatus) bool { return !info.IsDir() }),
+		webhdfs.WithCrawlerExclude(func(p string, info *webhdfs.FileStatus) bool { return info.Name() == "tmp" }))
+	if err != nil {
+		t.Fatalf("webhdfs Crawl failed: %s", err)
+	}
+	sort.Strings(crawled)
+	want := []string{"/a/1.txt", "/a/b/2.txt", "/c.txt"}
+	if strings.Join(crawled, ",") != strings.Join(want, ",") {
+		t.Errorf("Crawl: got %q, want %q", crawled, want)
+	}
+}
+
+func TestClient_ListStatusBatchPaginator(t *testing.T) {
+	c := getWebHDFSClient(t)
+	dir := HdfsBucket + "/test/paginator"
+	fsys := webhdfs.NewFS(c, HdfsBucket)
+	if err := fsys.MkdirAll("test/paginator", 0755); err != nil {
+		t.Fatalf("webhdfs MkdirAll failed: %s", err)
+	}
+	var want []string
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("%02d.txt", i)
+		f, err := fsys.Create(path.Join("test/paginator", name))
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+		}
+		f.Close()
+		want = append(want, name)
+	}
+
+	all, err := c.ListAll(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("webhdfs ListAll failed: %s", err)
+	}
+	var got []string
+	for _, fi := range all {
+		got = append(got, fi.Name())
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ListAll: got %q, want %q", got, want)
+	}
+
+	// resume listing after the 5th entry
+	it := c.NewListStatusIterator(context.Background(), &webhdfs.ListStatusBatchRequest{
+		ProxyUser:  c.ProxyUser(),
+		Path:       types.Pointer(dir),
+		StartAfter: types.Pointer(want[4]),
+	})
+	got = nil
+	for it.Next() {
+		got = append(got, it.FileStatus().Name())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("webhdfs ListStatusIterator failed: %s", err)
+	}
+	if strings.Join(got, ",") != strings.Join(want[5:], ",") {
+		t.Errorf("ListStatusIterator: got %q, want %q", got, want[5:])
+	}
+}
+
+func TestClient_Glob(t *testing.T) {
+	c := getWebHDFSClient(t)
+	dir := HdfsBucket + "/test/glob"
+	fsys := webhdfs.NewFS(c, HdfsBucket)
+	for _, name := range []string{"2024/01/part-0", "2024/01/part-1", "2024/02/_SUCCESS", "2025/01/part-0", "2023/01/part-0"} {
+		name = path.Join("test/glob", name)
+		if err := fsys.MkdirAll(path.Dir(name), 0755); err != nil {
+			t.Fatalf("webhdfs MkdirAll failed: %s", err)
+		}
+		f, err := fsys.Create(name)
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+		}
+		f.Close()
+	}
+
+	matches, err := c.Glob(context.Background(), dir+"/{2024,2025}/*/part-[0-9]*")
+	if err != nil {
+		t.Fatalf("webhdfs Glob failed: %s", err)
+	}
+	var got []string
+	for _, fi := range matches {
+		got = append(got, strings.TrimPrefix(path.Join(fi.PathPrefix, fi.PathSuffix), "/"+dir))
+	}
+	want := []string{"/2024/01/part-0", "/2024/01/part-1", "/2025/01/part-0"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Glob: got %q, want %q", got, want)
+	}
+}
+
+func TestClient_CopyFromLocal(t *testing.T) {
+	c := getWebHDFSClient(t)
+	dir := HdfsBucket + "/test/copy"
+
+	src, err := ioutil.TempDir("", "copy_src")
+	if err != nil {
+		t.Fatalf("create temp dir failed: %s", err)
+	}
+	defer os.RemoveAll(src)
+	files := map[string]string{"a/1.txt": "one", "a/b/2.txt": "two", "3.txt": "three"}
+	for name, data := range files {
+		name = filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatalf("create local dir failed: %s", err)
+		}
+		if err := ioutil.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatalf("write local file failed: %s", err)
+		}
+	}
+
+	result, err := c.CopyFromLocal(context.Background(), src, dir, webhdfs.WithCopierPreserve(true))
+	if err != nil {
+		t.Fatalf("webhdfs CopyFromLocal failed: %s", err)
+	}
+	if result.Copied != len(files) || result.Failed != 0 {
+		t.Fatalf("CopyFromLocal: copied %d, skipped %d, failed %d: %v", result.Copied, result.Skipped, result.Failed, result.Errors)
+	}
+
+	dst, err := ioutil.TempDir("", "copy_dst")
+	if err != nil {
+		t.Fatalf("create temp dir failed: %s", err)
+	}
+	defer os.RemoveAll(dst)
+	result, err = c.CopyToLocal(context.Background(), dir, dst, webhdfs.WithCopierMode(webhdfs.CopySkipExisting))
+	if err != nil {
+		t.Fatalf("webhdfs CopyToLocal failed: %s", err)
+	}
+	if result.Copied != len(files) || result.Failed != 0 {
+		t.Fatalf("CopyToLocal: copied %d, skipped %d, failed %d: %v", result.Copied, result.Skipped, result.Failed, result.Errors)
+	}
+	for name, data := range files {
+		readData, err := ioutil.ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("read local file failed: %s", err)
+		}
+		if string(readData) != data {
+			t.Errorf("%s, expected %q, got %q", name, data, readData)
+		}
+	}
+}
+
+func TestClient_ClusterCopy(t *testing.T) {
+	c := getWebHDFSClient(t)
+	src := HdfsBucket + "/test/cluster_copy/src"
+	dst := HdfsBucket + "/test/cluster_copy/dst"
+
+	files := map[string]string{"a/1.txt": "one", "a/b/2.txt": "two", "3.txt": "three"}
+	for name, data := range files {
+		w := c.NewWriter(context.Background(), path.Join(src, name), webhdfs.WithWriterOverwrite(true))
+		if _, err := w.Write([]byte(data)); err != nil {
+			t.Fatalf("webhdfs Write failed: %s", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("webhdfs Close failed: %s", err)
+		}
+	}
+
+	// the same cluster as both the source and the destination
+	result, err := c.CopyToCluster(context.Background(), c, src, dst,
+		webhdfs.WithClusterCopierPreserve(webhdfs.PreservePermission|webhdfs.PreserveTimes|webhdfs.PreserveReplication))
+	if err != nil {
+		t.Fatalf("webhdfs CopyToCluster failed: %s", err)
+	}
+	if result.Copied+result.Skipped != len(files) || result.Failed != 0 {
+		t.Fatalf("CopyToCluster: copied %d, skipped %d, failed %d: %v", result.Copied, result.Skipped, result.Failed, result.Errors)
+	}
+
+	result, err = c.CopyToCluster(context.Background(), c, src, dst)
+	if err != nil {
+		t.Fatalf("webhdfs CopyToCluster failed: %s", err)
+	}
+	if result.Skipped != len(files) || result.Copied != 0 {
+		t.Fatalf("CopyToCluster: copied %d, skipped %d, failed %d: %v", result.Copied, result.Skipped, result.Failed, result.Errors)
+	}
+}
+
+func TestClient_ReplicateSnapshotDiff(t *testing.T) {
+	c := getWebHDFSClient(t)
+	dir := HdfsBucket + "/test/replicate" + strconv.Itoa(time.Now().Nanosecond())
+	src := dir + "/src"
+	dst := dir + "/dst"
+	ctx := context.Background()
+
+	writeFile := func(name, data string) {
+		w := c.NewWriter(ctx, name, webhdfs.WithWriterOverwrite(true))
+		if _, err := w.Write([]byte(data)); err != nil {
+			t.Fatalf("webhdfs Write failed: %s", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("webhdfs Close failed: %s", err)
+		}
+	}
+	createSnapshot := func(dir, snapshot string) {
+		resp, err := c.CreateSnapshot(&webhdfs.CreateSnapshotRequest{
+			ProxyUser:    c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:         types.Pointer(dir),
+			Snapshotname: types.Pointer(snapshot),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs CreateSnapshot failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}
+	for _, d := range []string{src, dst} {
+		func() {
+			resp, err := c.Mkdirs(&webhdfs.MkdirsRequest{
+				ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+				Path:      types.Pointer(d),
+			})
+			if err != nil {
+				t.Fatalf("webhdfs Mkdirs failed: %s", err)
+			}
+			defer resp.Body.Close()
+		}()
+		func() {
+			resp, err := c.AllowSnapshot(&webhdfs.AllowSnapshotRequest{
+				ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+				Path:      types.Pointer(d),
+			})
+			if err != nil {
+				t.Fatalf("webhdfs AllowSnapshot failed: %s", err)
+			}
+			defer resp.Body.Close()
+		}()
+	}
+
+	writeFile(src+"/a/1.txt", "one")
+	writeFile(src+"/2.txt", "two")
+	createSnapshot(src, "s1")
+	if _, err := c.CopyToCluster(ctx, c, src, dst); err != nil {
+		t.Fatalf("webhdfs CopyToCluster failed: %s", err)
+	}
+	createSnapshot(dst, "s1")
+
+	func() {
+		resp, err := c.Rename(&webhdfs.RenameRequest{
+			ProxyUser:   c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:        types.Pointer(src + "/a"),
+			Destination: types.Pointer(src + "/b"),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Rename failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	writeFile(src+"/b/3.txt", "three")
+	createSnapshot(src, "s2")
+
+	result, err := c.ReplicateSnapshotDiff(ctx, c, src, "s1", "s2", dst)
+	if err != nil {
+		t.Fatalf("webhdfs ReplicateSnapshotDiff failed: %s", err)
+	}
+	t.Logf("renamed %d, deleted %d, copied %d", result.Renamed, result.Deleted, result.Copied)
+
+	statuses, err := c.ListAll(ctx, dst+"/b")
+	if err != nil {
+		t.Fatalf("webhdfs ListAll failed: %s", err)
+	}
+	var names []string
+	for _, st := range statuses {
+		names = append(names, st.PathSuffix)
+	}
+	if strings.Join(names, ",") != "1.txt,3.txt" {
+		t.Errorf("expected 1.txt,3.txt in %s, got %v", dst+"/b", names)
+	}
+}
+
+func TestClient_MoveToTrash(t *testing.T) {
+	c := getWebHDFSClient(t)
+	ctx := context.Background()
+	file := HdfsBucket + "/test/trash" + strconv.Itoa(time.Now().Nanosecond()) + ".txt"
+
+	w := c.NewWriter(ctx, file, webhdfs.WithWriterOverwrite(true))
+	if _, err := w.Write([]byte("trash")); err != nil {
+		t.Fatalf("webhdfs Write failed: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("webhdfs Close failed: %s", err)
+	}
+
+	trashPath, err := c.MoveToTrash(ctx, file)
+	if err != nil {
+		t.Fatalf("webhdfs MoveToTrash failed: %s", err)
+	}
+	t.Logf("%s moved to %s", file, trashPath)
+
+	trash, err := c.Trash(ctx, file)
+	if err != nil {
+		t.Fatalf("webhdfs Trash failed: %s", err)
+	}
+	restored, err := trash.Restore(ctx, trashPath)
+	if err != nil {
+		t.Fatalf("webhdfs Restore failed: %s", err)
+	}
+	if restored != path.Join("/", file) {
+		t.Errorf("expected restored to %s, got %s", path.Join("/", file), restored)
+	}
+
+	if _, err := c.MoveToTrash(ctx, file); err != nil {
+		t.Fatalf("webhdfs MoveToTrash failed: %s", err)
+	}
+	checkpoint, err := trash.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("webhdfs Checkpoint failed: %s", err)
+	}
+	checkpoints, err := trash.Checkpoints(ctx)
+	if err != nil {
+		t.Fatalf("webhdfs Checkpoints failed: %s", err)
+	}
+	var found bool
+	for _, cp := range checkpoints {
+		found = found || cp.Path == checkpoint
+	}
+	if !found {
+		t.Errorf("checkpoint %s not found in %v", checkpoint, checkpoints)
+	}
+	if _, err := trash.Expunge(ctx, 0); err != nil {
+		t.Fatalf("webhdfs Expunge failed: %s", err)
+	}
+}
+
+func TestClient_WriteFileAtomic(t *testing.T) {
+	c := getWebHDFSClient(t)
+	ctx := context.Background()
+	file := HdfsBucket + "/test/atomic.txt"
+
+	for _, data := range []string{"first version", "second"} {
+		if err := c.WriteFileAtomic(ctx, file, strings.NewReader(data), 0644); err != nil {
+			t.Fatalf("webhdfs WriteFileAtomic failed: %s", err)
+		}
+		func() {
+			resp, err := c.Open(&webhdfs.OpenRequest{
+				ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+				Path:      types.Pointer(file),
+			})
+			if err != nil {
+				t.Fatalf("webhdfs Open failed: %s", err)
+			}
+			defer resp.Body.Close()
+			readData, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read failed: %s", err)
+			}
+			if string(readData) != data {
+				t.Errorf("expected %q, got %q", data, readData)
+			}
+		}()
+	}
+}
+
+func TestClient_GetLinkTarget(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	link := HdfsBucket + "/test/link_target.link.txt"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader("Hello World!"),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.Delete(&webhdfs.DeleteRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(link),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Delete failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.CreateSymlink(&webhdfs.CreateSymlinkRequest{
+			ProxyUser:   c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:        types.Pointer("/" + file),
+			Destination: types.Pointer(link),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs CreateSymlink failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+
+	resp, err := c.GetLinkTarget(&webhdfs.GetLinkTargetRequest{
+		ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+		Path:      types.Pointer(link),
+	})
+	if err != nil {
+		t.Fatalf("webhdfs GetLinkTarget failed: %s", err)
+	}
+	defer resp.Body.Close()
+	if !strings.HasSuffix(resp.Path, "/"+file) {
+		t.Errorf("GetLinkTarget: got %q, want %q", resp.Path, "/"+file)
+	}
+}
+
+func TestClient_GetFileLinkStatus(t *testing.T) {
+	c := getWebHDFSClient(t)
+	file := HdfsBucket + "/test/found.txt"
+	link := HdfsBucket + "/test/file_link_status.link.txt"
+	func() {
+		resp, err := c.Create(&webhdfs.CreateRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(file),
+			Body:      strings.NewReader("Hello World!"),
+			Overwrite: types.Pointer(true),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.Delete(&webhdfs.DeleteRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(link),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Delete failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	func() {
+		resp, err := c.CreateSymlink(&webhdfs.CreateSymlinkRequest{
+			ProxyUser:   c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:        types.Pointer("/" + file),
+			Destination: types.Pointer(link),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs CreateSymlink failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+
+	resp, err := c.GetFileLinkStatus(&webhdfs.GetFileLinkStatusRequest{
+		ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+		Path:      types.Pointer(link),
+	})
+	if err != nil {
+		t.Fatalf("webhdfs GetFileLinkStatus failed: %s", err)
+	}
+	defer resp.Body.Close()
+	fi := resp.FileStatus
+	if fi.Type != webhdfs.FileTypeSymlink || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("GetFileLinkStatus: got type %s, want %s", fi.Type, webhdfs.FileTypeSymlink)
+	}
+	if !strings.HasSuffix(fi.Symlink, "/"+file) {
+		t.Errorf("GetFileLinkStatus: got symlink %q, want %q", fi.Symlink, "/"+file)
+	}
+}
+
+func TestClient_GetSnapshotDiffListing(t *testing.T) {
+	c := getWebHDFSClient(t)
+	dir := HdfsBucket + "/test/snapshot_diff_listing"
+	fsys := webhdfs.NewFS(c, HdfsBucket)
+	if err := fsys.MkdirAll("test/snapshot_diff_listing", 0755); err != nil {
+		t.Fatalf("webhdfs MkdirAll failed: %s", err)
+	}
+	func() {
+		resp, err := c.AllowSnapshot(&webhdfs.AllowSnapshotRequest{
+			ProxyUser: c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:      types.Pointer(dir),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs AllowSnapshot failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}()
+	snapshot := func(name string) {
+		func() {
+			resp, err := c.DeleteSnapshot(&webhdfs.DeleteSnapshotRequest{
+				ProxyUser:    c.ProxyUser(), // optional, user.name, The authenticated user
+				Path:         types.Pointer(dir),
+				Snapshotname: types.Pointer(name),
+			})
+			if err != nil {
+				return
+			}
+			defer resp.Body.Close()
+		}()
+		resp, err := c.CreateSnapshot(&webhdfs.CreateSnapshotRequest{
+			ProxyUser:    c.ProxyUser(), // optional, user.name, The authenticated user
+			Path:         types.Pointer(dir),
+			Snapshotname: types.Pointer(name),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs CreateSnapshot failed: %s", err)
+		}
+		defer resp.Body.Close()
+	}
+
+	var want []string
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("%02d.txt", i)
+		if err := fsys.Remove(path.Join("test/snapshot_diff_listing", name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("webhdfs Remove failed: %s", err)
+		}
+		want = append(want, name)
+	}
+	snapshot("snapshot.old")
+	for _, name := range want {
+		f, err := fsys.Create(path.Join("test/snapshot_diff_listing", name))
+		if err != nil {
+			t.Fatalf("webhdfs Create failed: %s", err)
+		}
+		f.Close()
+	}
+	snapshot("snapshot.new")
+
+	p := webhdfs.NewGetSnapshotDiffListingPaginator(c, &webhdfs.GetSnapshotDiffListingRequest{
+		ProxyUser:       c.ProxyUser(), // optional, user.name, The authenticated user
+		Path:            types.Pointer(dir),
+		Oldsnapshotname: types.Pointer("snapshot.old"),
+		Snapshotname:    types.Pointer("snapshot.new"),
+	})
+	var got []string
+	for p.HasNext() {
+		listing, err := p.Next(context.Background())
+		if err != nil {
+			t.Fatalf("webhdfs GetSnapshotDiffListing failed: %s", err)
+		}
+		if listing == nil {
+			break
+		}
+		for _, e := range listing.CreateList {
+			got = append(got, path.Base(e.SourcePath))
+		}
+	}
+	sort.Strings(got)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("GetSnapshotDiffListing: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/searKing/golang/go/exp/types"
+	time_ "github.com/searKing/golang/go/time"
+)
+
+// PreserveAttr is a set of file attributes kept by ClusterCopier, as -p of distcp.
+type PreserveAttr uint
+
+const (
+	PreserveOwner         PreserveAttr = 1 << iota // the user who is the owner, kept by SETOWNER
+	PreserveGroup                                  // the group owner, kept by SETOWNER
+	PreservePermission                             // the permission, kept by SETPERMISSION
+	PreserveTimes                                  // the access and modification times, kept by SETTIMES
+	PreserveReplication                            // the number of replications of files, kept by CREATE
+	PreserveXAttrs                                 // the extended attributes, kept by SETXATTR
+	PreserveStoragePolicy                          // the storage policy, kept by SETSTORAGEPOLICY
+
+	PreserveAll = PreserveOwner | PreserveGroup | PreservePermission | PreserveTimes |
+		PreserveReplication | PreserveXAttrs | PreserveStoragePolicy
+)
+
+// ClusterCopier copies directory trees from an HDFS cluster to another, as distcp -update,
+// each cluster accessed by a client of its own, so that they may be secured differently.
+// Directories are created first, then files are streamed from OPEN of the source into CREATE of the destination
+// concurrently, and a file failed to be copied is recorded in the result instead of aborting the copy.
+//
+// A file existing at the destination with the same length and GETFILECHECKSUM as the source is skipped,
+// files are created with the block size of the source for their checksums to be comparable.
+//
+//go:generate go-option -type "ClusterCopier"
+type ClusterCopier struct {
+	src, dst *Client
+
+	// options
+	concurrency  int
+	preserve     PreserveAttr
+	skipChecksum bool
+}
+
+// NewClusterCopier returns a ClusterCopier copying from the cluster of src to the cluster of dst.
+func NewClusterCopier(src, dst *Client, opts ...ClusterCopierOption) *ClusterCopier {
+	cc := &ClusterCopier{src: src, dst: dst, concurrency: DefaultCopyConcurrency}
+	cc.ApplyOptions(opts...)
+	if cc.concurrency <= 0 {
+		cc.concurrency = DefaultCopyConcurrency
+	}
+	return cc
+}
+
+// CopyToCluster copies the file or directory tree src to dstPath of the cluster of dst, see ClusterCopier.Copy.
+func (c *Client) CopyToCluster(ctx context.Context, dst *Client, src, dstPath string, opts ...ClusterCopierOption) (*CopyResult, error) {
+	return NewClusterCopier(c, dst, opts...).Copy(ctx, src, dstPath)
+}
+
+// Copy copies the file or directory tree src of the source cluster to dst of the destination cluster,
+// so that dst becomes a copy of src. Symbolic links are ignored.
+// The attributes set by WithClusterCopierPreserve are kept for files and directories, including files skipped.
+//
+// The error returned is of src or ctx, files failed are reported in the result.
+func (cc *ClusterCopier) Copy(ctx context.Context, src, dst string) (*CopyResult, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	src = path.Clean(src)
+	var dirs, files []copyJob
+	err := cc.src.Walk(ctx, src, func(name string, info *FileStatus, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Type == FileTypeSymlink {
+			return nil
+		}
+		job := copyJob{
+			src:   name,
+			dst:   path.Join(dst, name[len(src):]),
+			perm:  info.Permission,
+			atime: info.AccessTime,
+			mtime: info.ModificationTime,
+			info:  info,
+		}
+		if info.IsDir() {
+			dirs = append(dirs, job)
+		} else {
+			files = append(files, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CopyResult{}
+	for _, dir := range dirs {
+		if err := cc.mkdirs(ctx, dir); err != nil {
+			return result, &fs.PathError{Op: "mkdir", Path: dir.dst, Err: err}
+		}
+		if err := cc.preserveAttrs(ctx, dir); err != nil {
+			result.Errors = append(result.Errors, &fs.PathError{Op: "preserve", Path: dir.src, Err: err})
+		}
+	}
+	runCopyJobs(ctx, cc.concurrency, files, result, cc.copy)
+
+	// times of directories are changed by the files copied into
+	if cc.preserve&PreserveTimes != 0 {
+		for i := len(dirs) - 1; i >= 0; i-- {
+			if err := cc.dst.setFileTimes(ctx, dirs[i].dst, accessTime(dirs[i].atime), &dirs[i].mtime); err != nil {
+				result.Errors = append(result.Errors, &fs.PathError{Op: "chtimes", Path: dirs[i].src, Err: err})
+			}
+		}
+	}
+	return result, ctx.Err()
+}
+
+// copy copies the file of job unless unchanged, and keeps its attributes.
+func (cc *ClusterCopier) copy(ctx context.Context, job copyJob) (skipped bool, n int64, err error) {
+	skipped, err = cc.unchanged(ctx, job)
+	if err != nil {
+		return false, 0, err
+	}
+	if !skipped {
+		n, err = cc.transfer(ctx, job)
+		if err != nil {
+			return false, n, err
+		}
+	}
+	if err := cc.preserveAttrs(ctx, job); err != nil {
+		return false, n, err
+	}
+	return skipped, n, nil
+}
+
+// unchanged reports whether the file of job exists at the destination with the same length and checksum.
+// Checksums of different algorithms, such as of different block sizes, are taken as changed.
+func (cc *ClusterCopier) unchanged(ctx context.Context, job copyJob) (bool, error) {
+	info, err := cc.dst.fileStatus(ctx, job.dst)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if info.IsDir() || info.Length != job.info.Length {
+		return false, nil
+	}
+	if cc.skipChecksum || info.Length == 0 {
+		return true, nil
+	}
+	srcSum, err := cc.src.fileChecksum(ctx, job.src)
+	if err != nil {
+		return false, err
+	}
+	dstSum, err := cc.dst.fileChecksum(ctx, job.dst)
+	if err != nil {
+		return false, err
+	}
+	return equalChecksum(srcSum, dstSum), nil
+}
+
+// transfer streams the file of job from the source to the destination, replacing the existing one.
+func (cc *ClusterCopier) transfer(ctx context.Context, job copyJob) (int64, error) {
+	resp, err := cc.src.OpenWithContext(ctx, &OpenRequest{
+		ProxyUser: cc.src.ProxyUser(),
+		Path:      types.Pointer(job.src),
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	opts := []WriterOption{WithWriterOverwrite(true), WithWriterBlocksize(job.info.BlockSize)}
+	if cc.preserve&PreservePermission != 0 {
+		opts = append(opts, WithWriterPermission(job.perm))
+	}
+	if cc.preserve&PreserveReplication != 0 {
+		opts = append(opts, WithWriterReplication(int(job.info.Replication)))
+	}
+	w := cc.dst.NewWriter(ctx, job.dst, opts...)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		w.Close()
+		return w.Written(), err
+	}
+	if err := w.Close(); err != nil {
+		return w.Written(), err
+	}
+	if w.Written() != job.info.Length {
+		return w.Written(), fmt.Errorf("source changed during copy, %d bytes copied of %d", w.Written(), job.info.Length)
+	}
+	return w.Written(), nil
+}
+
+// preserveAttrs sets the attributes of the source of job to the destination, as set by WithClusterCopierPreserve.
+// The times of a directory are left to be set after all files copied.
+func (cc *ClusterCopier) preserveAttrs(ctx context.Context, job copyJob) error {
+	info := job.info
+	if cc.preserve&(PreserveOwner|PreserveGroup) != 0 {
+		req := &SetOwnerRequest{
+			ProxyUser: cc.dst.ProxyUser(),
+			Path:      types.Pointer(job.dst),
+		}
+		if cc.preserve&PreserveOwner != 0 {
+			req.Owner = types.Pointer(info.Owner)
+		}
+		if cc.preserve&PreserveGroup != 0 {
+			req.Group = types.Pointer(info.Group)
+		}
+		resp, err := cc.dst.SetOwnerWithContext(ctx, req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	// the permission of CREATE and MKDIRS is masked by fs.permissions.umask-mode
+	if cc.preserve&PreservePermission != 0 {
+		if err := cc.dst.setFilePermission(ctx, job.dst, job.perm); err != nil {
+			return err
+		}
+	}
+	// replication of a file skipped is not set by CREATE
+	if cc.preserve&PreserveReplication != 0 && !info.IsDir() {
+		resp, err := cc.dst.SetReplicationWithContext(ctx, &SetReplicationRequest{
+			ProxyUser:   cc.dst.ProxyUser(),
+			Path:        types.Pointer(job.dst),
+			Replication: types.Pointer(int(info.Replication)),
+		})
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	if cc.preserve&PreserveXAttrs != 0 {
+		if err := cc.copyXAttrs(ctx, job); err != nil {
+			return err
+		}
+	}
+	if cc.preserve&PreserveStoragePolicy != 0 {
+		if err := cc.copyStoragePolicy(ctx, job); err != nil {
+			return err
+		}
+	}
+	if cc.preserve&PreserveTimes != 0 && !info.IsDir() {
+		if err := cc.dst.setFileTimes(ctx, job.dst, accessTime(job.atime), &job.mtime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyXAttrs sets the extended attributes of the source of job to the destination,
+// creating or replacing them, while others of the destination are left as they are.
+func (cc *ClusterCopier) copyXAttrs(ctx context.Context, job copyJob) error {
+	srcAttrs, err := cc.src.allXAttrs(ctx, job.src)
+	if err != nil {
+		return err
+	}
+	if len(srcAttrs) == 0 {
+		return nil
+	}
+	dstAttrs, err := cc.dst.allXAttrs(ctx, job.dst)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]string, len(dstAttrs))
+	for _, attr := range dstAttrs {
+		existing[attr.Name] = attr.Value
+	}
+	for _, attr := range srcAttrs {
+		flag := XAttrSetFlagCreate
+		if value, ok := existing[attr.Name]; ok {
+			if value == attr.Value {
+				continue
+			}
+			flag = XAttrSetFlagReplace
+		}
+		resp, err := cc.dst.SetXAttrWithContext(ctx, &SetXAttrRequest{
+			ProxyUser:  cc.dst.ProxyUser(),
+			Path:       types.Pointer(job.dst),
+			XAttrName:  types.Pointer(attr.Name),
+			XAttrValue: types.Pointer(attr.Value),
+			XAttrFlag:  flag.New(),
+		})
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// copyStoragePolicy sets the storage policy of the source of job to the destination.
+func (cc *ClusterCopier) copyStoragePolicy(ctx context.Context, job copyJob) error {
+	resp, err := cc.src.GetStoragePolicyWithContext(ctx, &GetStoragePolicyRequest{
+		ProxyUser: cc.src.ProxyUser(),
+		Path:      types.Pointer(job.src),
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	policy := resp.BlockStoragePolicy.BlockStoragePolicy.Name
+	if policy == "" {
+		return nil
+	}
+
+	setResp, err := cc.dst.SetStoragePolicyWithContext(ctx, &SetStoragePolicyRequest{
+		ProxyUser:     cc.dst.ProxyUser(),
+		Path:          types.Pointer(job.dst),
+		StoragePolicy: types.Pointer(policy),
+	})
+	if err != nil {
+		return err
+	}
+	return setResp.Body.Close()
+}
+
+// mkdirs creates the directory of job on the destination.
+func (cc *ClusterCopier) mkdirs(ctx context.Context, job copyJob) error {
+	resp, err := cc.dst.MkdirsWithContext(ctx, &MkdirsRequest{
+		ProxyUser: cc.dst.ProxyUser(),
+		Path:      types.Pointer(job.dst),
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if !resp.Boolean {
+		return syscall.ENOTDIR
+	}
+	return nil
+}
+
+// allXAttrs returns the extended attributes of the file path, with values encoded in hex.
+func (c *Client) allXAttrs(ctx context.Context, path string) (XAttrs, error) {
+	resp, err := c.GetAllXAttrsWithContext(ctx, &GetAllXAttrsRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+		Encoding:  XAttrValueEncodingHex.New(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return resp.XAttrs, nil
+}
+
+// accessTime returns atime to be set by SETTIMES, or nil if the access time is not recorded,
+// as by a namenode with dfs.namenode.accesstime.precision of 0.
+func accessTime(atime time_.UnixTimeMillisecond) *time_.UnixTimeMillisecond {
+	if !atime.After(time.Unix(0, 0)) {
+		return nil
+	}
+	return &atime
+}
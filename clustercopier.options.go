@@ -0,0 +1,27 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+// WithClusterCopierConcurrency sets the max number of files transferred at the same time.
+func WithClusterCopierConcurrency(concurrency int) ClusterCopierOption {
+	return ClusterCopierOptionFunc(func(cc *ClusterCopier) {
+		cc.concurrency = concurrency
+	})
+}
+
+// WithClusterCopierPreserve sets the attributes of files and directories kept, none by default.
+func WithClusterCopierPreserve(preserve PreserveAttr) ClusterCopierOption {
+	return ClusterCopierOptionFunc(func(cc *ClusterCopier) {
+		cc.preserve = preserve
+	})
+}
+
+// WithClusterCopierSkipChecksum sets whether files existing at the destination are compared by length only,
+// without GETFILECHECKSUM, as -skipcrccheck of distcp.
+func WithClusterCopierSkipChecksum(skip bool) ClusterCopierOption {
+	return ClusterCopierOptionFunc(func(cc *ClusterCopier) {
+		cc.skipChecksum = skip
+	})
+}
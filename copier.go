@@ -0,0 +1,406 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/searKing/golang/go/exp/types"
+	time_ "github.com/searKing/golang/go/time"
+)
+
+const DefaultCopyConcurrency = 4
+
+// CopyMode tells what to do with a file already existing at the destination of a copy.
+type CopyMode int
+
+const (
+	CopyOverwrite     CopyMode = iota // replace the existing file
+	CopySkipExisting                  // keep the existing file
+	CopyUpdateIfNewer                 // replace the existing file only if the source is modified later
+)
+
+// CopyResult summarizes a copy of files.
+type CopyResult struct {
+	Copied  int   // number of files copied
+	Skipped int   // number of files skipped as existing at the destination
+	Failed  int   // number of files failed to be copied
+	Bytes   int64 // number of bytes copied
+
+	Errors []error // errors of the files failed, as *fs.PathError of the source
+}
+
+// Copier copies directory trees between the local file system and HDFS, as put -r and get -r of hadoop fs.
+// Directories are created first, then files are transferred concurrently,
+// and a file failed to be copied is recorded in the result instead of aborting the copy.
+//
+//go:generate go-option -type "Copier"
+type Copier struct {
+	c *Client
+
+	// options
+	concurrency int
+	mode        CopyMode
+	preserve    bool
+}
+
+// NewCopier returns a Copier copying by c.
+func NewCopier(c *Client, opts ...CopierOption) *Copier {
+	cp := &Copier{c: c, concurrency: DefaultCopyConcurrency}
+	cp.ApplyOptions(opts...)
+	if cp.concurrency <= 0 {
+		cp.concurrency = DefaultCopyConcurrency
+	}
+	return cp
+}
+
+// CopyFromLocal copies the local file or directory tree src to dst, see Copier.CopyFromLocal.
+func (c *Client) CopyFromLocal(ctx context.Context, src, dst string, opts ...CopierOption) (*CopyResult, error) {
+	return NewCopier(c, opts...).CopyFromLocal(ctx, src, dst)
+}
+
+// CopyToLocal copies the file or directory tree src to the local dst, see Copier.CopyToLocal.
+func (c *Client) CopyToLocal(ctx context.Context, src, dst string, opts ...CopierOption) (*CopyResult, error) {
+	return NewCopier(c, opts...).CopyToLocal(ctx, src, dst)
+}
+
+// copyJob is a file to be copied.
+type copyJob struct {
+	src, dst string
+	perm     Permission
+	atime    time_.UnixTimeMillisecond
+	mtime    time_.UnixTimeMillisecond
+	info     *FileStatus // status of the source on HDFS, nil if local
+}
+
+// CopyFromLocal copies the local file or directory tree src to dst, so that dst becomes a copy of src.
+// Directories are created by MKDIRS, and files by CREATE and APPEND as by Writer.
+// Only regular files are copied, others such as symbolic links are ignored.
+// The permission and the times of files are kept by SETPERMISSION and SETTIMES if WithCopierPreserve is set.
+//
+// The error returned is of src or ctx, files failed are reported in the result.
+func (cp *Copier) CopyFromLocal(ctx context.Context, src, dst string) (*CopyResult, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	var dirs, files []copyJob
+	err := filepath.WalkDir(src, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, name)
+		if err != nil {
+			return err
+		}
+		job := copyJob{
+			src:   name,
+			dst:   path.Join(dst, filepath.ToSlash(rel)),
+			perm:  permissionFromFileMode(info.Mode()),
+			mtime: time_.UnixTimeMillisecond{Time: info.ModTime()},
+		}
+		if d.IsDir() {
+			dirs = append(dirs, job)
+		} else {
+			files = append(files, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CopyResult{}
+	for _, dir := range dirs {
+		if err := cp.mkdirs(ctx, dir); err != nil {
+			return result, &fs.PathError{Op: "mkdir", Path: dir.dst, Err: err}
+		}
+	}
+	runCopyJobs(ctx, cp.concurrency, files, result, cp.put)
+
+	// times of directories are changed by the files copied into
+	if cp.preserve {
+		for i := len(dirs) - 1; i >= 0; i-- {
+			if err := cp.c.setFileTimes(ctx, dirs[i].dst, nil, &dirs[i].mtime); err != nil {
+				result.Errors = append(result.Errors, &fs.PathError{Op: "chtimes", Path: dirs[i].src, Err: err})
+			}
+		}
+	}
+	return result, ctx.Err()
+}
+
+// CopyToLocal copies the file or directory tree src to the local dst, so that dst becomes a copy of src.
+// Directories are created by os.MkdirAll, and files are downloaded as by Downloader.
+// Symbolic links are ignored.
+// The permission and the times of files are kept by os.Chmod and os.Chtimes if WithCopierPreserve is set.
+//
+// The error returned is of src or ctx, files failed are reported in the result.
+func (cp *Copier) CopyToLocal(ctx context.Context, src, dst string) (*CopyResult, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	src = path.Clean(src)
+	var dirs, files []copyJob
+	err := cp.c.Walk(ctx, src, func(name string, info *FileStatus, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Type == FileTypeSymlink {
+			return nil
+		}
+		rel := name[len(src):]
+		job := copyJob{
+			src:   name,
+			dst:   filepath.Join(dst, filepath.FromSlash(rel)),
+			perm:  info.Permission,
+			atime: info.AccessTime,
+			mtime: info.ModificationTime,
+			info:  info,
+		}
+		if info.IsDir() {
+			dirs = append(dirs, job)
+		} else {
+			files = append(files, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CopyResult{}
+	for _, dir := range dirs {
+		perm := os.FileMode(DefaultPermissionDirectory)
+		if cp.preserve {
+			perm = dir.perm.fileMode()
+		}
+		if err := os.MkdirAll(dir.dst, perm); err != nil {
+			return result, err
+		}
+	}
+	runCopyJobs(ctx, cp.concurrency, files, result, cp.get)
+
+	// times of directories are changed by the files copied into
+	if cp.preserve {
+		for i := len(dirs) - 1; i >= 0; i-- {
+			if err := os.Chtimes(dirs[i].dst, dirs[i].mtime.Time, dirs[i].mtime.Time); err != nil {
+				result.Errors = append(result.Errors, &fs.PathError{Op: "chtimes", Path: dirs[i].src, Err: err})
+			}
+		}
+	}
+	return result, ctx.Err()
+}
+
+// runCopyJobs copies files by copy on concurrency workers, and sums up the result.
+// copy returns whether the file is skipped, and the bytes copied.
+func runCopyJobs(ctx context.Context, concurrency int, files []copyJob, result *CopyResult,
+	copy func(ctx context.Context, job copyJob) (skipped bool, n int64, err error)) {
+	var mu sync.Mutex
+	jobs := make(chan copyJob)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(files); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				skipped, n, err := copy(ctx, job)
+				mu.Lock()
+				result.Bytes += n
+				switch {
+				case err != nil:
+					result.Failed++
+					result.Errors = append(result.Errors, &fs.PathError{Op: "copy", Path: job.src, Err: err})
+				case skipped:
+					result.Skipped++
+				default:
+					result.Copied++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+L:
+	for _, job := range files {
+		select {
+		case jobs <- job:
+		case <-ctx.Done():
+			break L
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// put copies the local file of job to HDFS.
+func (cp *Copier) put(ctx context.Context, job copyJob) (skipped bool, n int64, err error) {
+	if cp.mode != CopyOverwrite {
+		info, err := cp.c.fileStatus(ctx, job.dst)
+		if err == nil && cp.skip(info.ModificationTime, job.mtime) {
+			return true, 0, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, 0, err
+		}
+	}
+
+	f, err := os.Open(job.src)
+	if err != nil {
+		return false, 0, err
+	}
+	defer f.Close()
+	opts := []WriterOption{WithWriterOverwrite(true)}
+	if cp.preserve {
+		opts = append(opts, WithWriterPermission(job.perm))
+	}
+	w := cp.c.NewWriter(ctx, job.dst, opts...)
+	if _, err := io.Copy(w, f); err != nil {
+		w.Close()
+		return false, w.Written(), err
+	}
+	if err := w.Close(); err != nil {
+		return false, w.Written(), err
+	}
+
+	if cp.preserve {
+		// the permission of CREATE is masked by fs.permissions.umask-mode
+		if err := cp.c.setFilePermission(ctx, job.dst, job.perm); err != nil {
+			return false, w.Written(), err
+		}
+		if err := cp.c.setFileTimes(ctx, job.dst, nil, &job.mtime); err != nil {
+			return false, w.Written(), err
+		}
+	}
+	return false, w.Written(), nil
+}
+
+// get copies the HDFS file of job to the local file system.
+// The file is downloaded to a temporary file in the same directory, renamed to the destination once complete,
+// so that an existing file is never left truncated or partially written.
+func (cp *Copier) get(ctx context.Context, job copyJob) (skipped bool, n int64, err error) {
+	if cp.mode != CopyOverwrite {
+		info, err := os.Stat(job.dst)
+		if err == nil && cp.skip(time_.UnixTimeMillisecond{Time: info.ModTime()}, job.mtime) {
+			return true, 0, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, 0, err
+		}
+	}
+
+	perm := os.FileMode(DefaultPermissionFile)
+	if cp.preserve {
+		perm = job.perm.fileMode()
+	}
+	f, err := os.CreateTemp(filepath.Dir(job.dst), "."+filepath.Base(job.dst)+".*.tmp")
+	if err != nil {
+		return false, 0, err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	n, err = cp.c.Download(ctx, job.src, f, WithDownloaderConcurrency(1))
+	if err != nil {
+		return false, n, err
+	}
+	if err = f.Close(); err != nil {
+		return false, n, err
+	}
+
+	// a temporary file is created with 0600
+	if err = os.Chmod(f.Name(), perm); err != nil {
+		return false, n, err
+	}
+	if cp.preserve {
+		if err = os.Chtimes(f.Name(), job.atime.Time, job.mtime.Time); err != nil {
+			return false, n, err
+		}
+	}
+	if err = os.Rename(f.Name(), job.dst); err != nil {
+		return false, n, err
+	}
+	return false, n, nil
+}
+
+// skip reports whether a file existing at the destination modified at dstTime is kept,
+// as the source is modified at srcTime.
+func (cp *Copier) skip(dstTime, srcTime time_.UnixTimeMillisecond) bool {
+	switch cp.mode {
+	case CopySkipExisting:
+		return true
+	case CopyUpdateIfNewer:
+		// HDFS keeps times in milliseconds
+		return !srcTime.Truncate(time.Millisecond).After(dstTime.Truncate(time.Millisecond))
+	default:
+		return false
+	}
+}
+
+// setFileTimes sets the access and modification times of the file path, a nil time leaves it unchanged.
+func (c *Client) setFileTimes(ctx context.Context, path string, atime, mtime *time_.UnixTimeMillisecond) error {
+	resp, err := c.SetTimesWithContext(ctx, &SetTimesRequest{
+		ProxyUser:        c.ProxyUser(),
+		Path:             types.Pointer(path),
+		Accesstime:       atime,
+		Modificationtime: mtime,
+	})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// setFilePermission sets the permission of the file path.
+func (c *Client) setFilePermission(ctx context.Context, path string, perm Permission) error {
+	resp, err := c.SetPermissionWithContext(ctx, &SetPermissionRequest{
+		ProxyUser:  c.ProxyUser(),
+		Path:       types.Pointer(path),
+		Permission: perm.New(),
+	})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// mkdirs creates the directory of job on HDFS, with its permission if preserved.
+func (cp *Copier) mkdirs(ctx context.Context, job copyJob) error {
+	resp, err := cp.c.MkdirsWithContext(ctx, &MkdirsRequest{
+		ProxyUser: cp.c.ProxyUser(),
+		Path:      types.Pointer(job.dst),
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if !resp.Boolean {
+		return syscall.ENOTDIR
+	}
+	if cp.preserve {
+		// the permission of MKDIRS is masked, and never applied to an existing directory
+		return cp.c.setFilePermission(ctx, job.dst, job.perm)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+// WithCopierConcurrency sets the max number of files transferred at the same time.
+func WithCopierConcurrency(concurrency int) CopierOption {
+	return CopierOptionFunc(func(cp *Copier) {
+		cp.concurrency = concurrency
+	})
+}
+
+// WithCopierMode sets what to do with a file already existing at the destination, CopyOverwrite by default.
+func WithCopierMode(mode CopyMode) CopierOption {
+	return CopierOptionFunc(func(cp *Copier) {
+		cp.mode = mode
+	})
+}
+
+// WithCopierPreserve sets whether the permission and the modification time of files are kept.
+func WithCopierPreserve(preserve bool) CopierOption {
+	return CopierOptionFunc(func(cp *Copier) {
+		cp.preserve = preserve
+	})
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sync"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+const DefaultCrawlConcurrency = 16
+
+// CrawlFunc is the type of the function called by Crawl for each file or directory found.
+// It may be called concurrently from multiple goroutines, and the crawl is stopped if it returns an error.
+type CrawlFunc func(path string, info *FileStatus) error
+
+// CrawlFilter reports whether the file or directory at path is selected.
+type CrawlFilter func(path string, info *FileStatus) bool
+
+// CrawlError collects the directories failed to be listed during a crawl.
+type CrawlError struct {
+	Errors []*fs.PathError
+}
+
+func (e *CrawlError) Error() string {
+	if len(e.Errors) == 1 {
+		return "crawl: " + e.Errors[0].Error()
+	}
+	return fmt.Sprintf("crawl: %d directories failed, first: %s", len(e.Errors), e.Errors[0])
+}
+
+// Crawler lists a directory tree by LISTSTATUS_BATCH calls fanned out across a bounded pool of workers,
+// for namespaces too large to be walked sequentially.
+// Unlike Walk, files are found in no particular order, and a directory failed to be listed
+// is recorded instead of aborting the crawl. Symbolic links are never followed.
+//
+//go:generate go-option -type "Crawler"
+type Crawler struct {
+	c *Client
+
+	// options
+	concurrency int
+	maxDepth    int
+	include     CrawlFilter
+	exclude     CrawlFilter
+}
+
+// NewCrawler returns a Crawler crawling by c.
+func NewCrawler(c *Client, opts ...CrawlerOption) *Crawler {
+	cr := &Crawler{
+		c:           c,
+		concurrency: DefaultCrawlConcurrency,
+		maxDepth:    -1,
+	}
+	cr.ApplyOptions(opts...)
+	if cr.concurrency <= 0 {
+		cr.concurrency = DefaultCrawlConcurrency
+	}
+	return cr
+}
+
+// Crawl crawls the directory tree rooted at root, see Crawler.Crawl.
+func (c *Client) Crawl(ctx context.Context, root string, fn CrawlFunc, opts ...CrawlerOption) error {
+	return NewCrawler(c, opts...).Crawl(ctx, root, fn)
+}
+
+// crawlDir is a directory waiting to be listed.
+type crawlDir struct {
+	path  string
+	depth int
+}
+
+// Crawl crawls the directory tree rooted at root, calling fn for root and each file or directory in the tree
+// selected by the filters. Root is at depth 0, and the files it contains at depth 1.
+// An excluded directory is not descended into, while a directory not included is still crawled.
+//
+// Crawl returns the first error returned by fn, or the error of ctx if done.
+// Otherwise, the directories failed to be listed are returned as a *CrawlError after all the others crawled.
+func (cr *Crawler) Crawl(ctx context.Context, root string, fn CrawlFunc) error {
+	if ctx == nil {
+		panic("nil context")
+	}
+	info, err := cr.c.fileStatus(ctx, root)
+	if err != nil {
+		return &fs.PathError{Op: "crawl", Path: root, Err: err}
+	}
+	if cr.exclude != nil && cr.exclude(root, info) {
+		return nil
+	}
+	if cr.include == nil || cr.include(root, info) {
+		if err := fn(root, info); err != nil {
+			return err
+		}
+	}
+	if !info.IsDir() || cr.maxDepth == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+	queue := []crawlDir{{path: root}}
+	pending := 1 // directories queued or being listed
+	var firstErr error
+	var crawlErr CrawlError
+
+	go func() {
+		<-ctx.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		cond.Broadcast()
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < cr.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				for len(queue) == 0 && pending > 0 && ctx.Err() == nil {
+					cond.Wait()
+				}
+				if pending == 0 || ctx.Err() != nil {
+					mu.Unlock()
+					return
+				}
+				// last in first out, so the queue grows no more than the depth of the tree times its width
+				dir := queue[len(queue)-1]
+				queue = queue[:len(queue)-1]
+				mu.Unlock()
+
+				subdirs, listErr, err := cr.crawlDir(ctx, dir, fn)
+
+				mu.Lock()
+				pending--
+				if listErr != nil && ctx.Err() == nil {
+					crawlErr.Errors = append(crawlErr.Errors, listErr)
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				queue = append(queue, subdirs...)
+				pending += len(subdirs)
+				cond.Broadcast()
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(crawlErr.Errors) > 0 {
+		return &crawlErr
+	}
+	return nil
+}
+
+// crawlDir lists dir batch by batch, calling fn for the files selected,
+// and returns the subdirectories to be crawled.
+// A failure listing dir is returned as listErr, along with the subdirectories found before,
+// and err is the error returned by fn.
+func (cr *Crawler) crawlDir(ctx context.Context, dir crawlDir, fn CrawlFunc) (subdirs []crawlDir, listErr *fs.PathError, err error) {
+	var startAfter *string
+	for {
+		statuses, more, err := cr.c.listDirBatch(ctx, dir.path, startAfter)
+		if err != nil {
+			return subdirs, &fs.PathError{Op: "crawl", Path: dir.path, Err: err}, nil
+		}
+		for i := range statuses {
+			info := &statuses[i]
+			name := path.Join(dir.path, info.PathSuffix)
+			if cr.exclude != nil && cr.exclude(name, info) {
+				continue
+			}
+			if cr.include == nil || cr.include(name, info) {
+				if err := fn(name, info); err != nil {
+					return subdirs, nil, err
+				}
+			}
+			if info.IsDir() && (cr.maxDepth < 0 || dir.depth+1 < cr.maxDepth) {
+				subdirs = append(subdirs, crawlDir{path: name, depth: dir.depth + 1})
+			}
+		}
+		if !more {
+			return subdirs, nil, nil
+		}
+		startAfter = types.Pointer(statuses[len(statuses)-1].PathSuffix)
+	}
+}
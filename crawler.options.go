@@ -0,0 +1,34 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+// WithCrawlerConcurrency sets the max number of directories listed at the same time.
+func WithCrawlerConcurrency(concurrency int) CrawlerOption {
+	return CrawlerOptionFunc(func(cr *Crawler) {
+		cr.concurrency = concurrency
+	})
+}
+
+// WithCrawlerMaxDepth sets the max depth of files crawled, root is at depth 0, a negative depth means no limit.
+func WithCrawlerMaxDepth(maxDepth int) CrawlerOption {
+	return CrawlerOptionFunc(func(cr *Crawler) {
+		cr.maxDepth = maxDepth
+	})
+}
+
+// WithCrawlerInclude sets the filter of files reported, all files are reported if not set.
+// Directories not included are still crawled into.
+func WithCrawlerInclude(include CrawlFilter) CrawlerOption {
+	return CrawlerOptionFunc(func(cr *Crawler) {
+		cr.include = include
+	})
+}
+
+// WithCrawlerExclude sets the filter of files skipped, excluded directories are not crawled into.
+func WithCrawlerExclude(exclude CrawlFilter) CrawlerOption {
+	return CrawlerOptionFunc(func(cr *Crawler) {
+		cr.exclude = exclude
+	})
+}
@@ -0,0 +1,230 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+const (
+	DefaultDownloadPartSize    = 64 << 20 // 64MB
+	DefaultDownloadConcurrency = 5
+	DefaultDownloadMaxRetries  = 3
+)
+
+// Downloader downloads a file by ranged OPEN of its parts concurrently.
+// A file is split along the boundaries of its blocks got by GETFILEBLOCKLOCATIONS,
+// or into parts of fixed size if the block locations are unavailable.
+//
+//go:generate go-option -type "Downloader"
+type Downloader struct {
+	c *Client
+
+	// options
+	partSize    int64
+	concurrency int
+	maxRetries  int
+	progress    func(downloaded, total int64)
+}
+
+// NewDownloader returns a Downloader downloading files by c.
+func NewDownloader(c *Client, opts ...DownloaderOption) *Downloader {
+	d := &Downloader{
+		c:           c,
+		partSize:    DefaultDownloadPartSize,
+		concurrency: DefaultDownloadConcurrency,
+		maxRetries:  DefaultDownloadMaxRetries,
+	}
+	d.ApplyOptions(opts...)
+	if d.partSize <= 0 {
+		d.partSize = DefaultDownloadPartSize
+	}
+	if d.concurrency <= 0 {
+		d.concurrency = DefaultDownloadConcurrency
+	}
+	return d
+}
+
+// Download downloads the file path into w, see Downloader.Download.
+func (c *Client) Download(ctx context.Context, path string, w io.WriterAt, opts ...DownloaderOption) (int64, error) {
+	return NewDownloader(c, opts...).Download(ctx, path, w)
+}
+
+// filePart is a byte range of a file.
+type filePart struct {
+	Offset int64
+	Length int64
+}
+
+// Download downloads the file path into w, parts are written at their offsets in the file concurrently.
+// It returns the number of bytes downloaded and the first error encountered, if any.
+func (d *Downloader) Download(ctx context.Context, path string, w io.WriterAt) (int64, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	resp, err := d.c.GetFileStatusWithContext(ctx, &GetFileStatusRequest{
+		ProxyUser: d.c.ProxyUser(),
+		Path:      types.Pointer(path),
+	})
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.FileStatus.IsDir() {
+		return 0, fmt.Errorf("%s is a directory", path)
+	}
+	total := resp.FileStatus.Length
+	if total == 0 {
+		return 0, nil
+	}
+	return d.download(ctx, path, total, d.split(ctx, path, total), w)
+}
+
+// split returns the parts of the file path, by blocks if possible.
+func (d *Downloader) split(ctx context.Context, path string, total int64) []filePart {
+	resp, err := d.c.GetFileBlockLocationsWithContext(ctx, &GetFileBlockLocationsRequest{
+		ProxyUser: d.c.ProxyUser(),
+		Path:      types.Pointer(path),
+	})
+	if err == nil {
+		resp.Body.Close()
+		var parts []filePart
+		var offset int64
+		for _, block := range resp.BlockLocations.BlockLocations {
+			if block.Offset != offset || block.Length <= 0 {
+				break
+			}
+			parts = append(parts, filePart{Offset: block.Offset, Length: block.Length})
+			offset += block.Length
+		}
+		// blocks may not cover the whole file, such as a file being written
+		if offset == total {
+			return parts
+		}
+	}
+
+	var parts []filePart
+	for offset := int64(0); offset < total; offset += d.partSize {
+		length := d.partSize
+		if offset+length > total {
+			length = total - offset
+		}
+		parts = append(parts, filePart{Offset: offset, Length: length})
+	}
+	return parts
+}
+
+func (d *Downloader) download(ctx context.Context, path string, total int64, parts []filePart, w io.WriterAt) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var downloaded int64
+	var firstErr error
+	progress := func(n int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		downloaded += n
+		if d.progress != nil {
+			d.progress(downloaded, total)
+		}
+	}
+
+	partCh := make(chan filePart)
+	var wg sync.WaitGroup
+	for i := 0; i < d.concurrency && i < len(parts); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for part := range partCh {
+				if err := d.downloadPart(ctx, path, part, w, progress); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("download part [%d, %d): %w", part.Offset, part.Offset+part.Length, err)
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+
+L:
+	for _, part := range parts {
+		select {
+		case partCh <- part:
+		case <-ctx.Done():
+			break L
+		}
+	}
+	close(partCh)
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return downloaded, firstErr
+}
+
+// downloadPart downloads part into w, a failed part is tried again from the byte it stopped at.
+func (d *Downloader) downloadPart(ctx context.Context, path string, part filePart, w io.WriterAt, progress func(n int64)) error {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := sleepWithContext(ctx, retryBackoff(attempt)); err != nil {
+				return err
+			}
+		}
+		n, err := d.c.readPart(ctx, path, part, w, progress)
+		part.Offset += n
+		part.Length -= n
+		if err == nil {
+			return nil
+		}
+		if !isRetryableError(err) || attempt >= d.maxRetries {
+			return err
+		}
+	}
+}
+
+// readPart reads part of the file path by a ranged OPEN into w at the same offset.
+func (c *Client) readPart(ctx context.Context, path string, part filePart, w io.WriterAt, progress func(n int64)) (int64, error) {
+	resp, err := c.OpenWithContext(ctx, &OpenRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+		Offset:    types.Pointer(part.Offset),
+		Length:    types.Pointer(part.Length),
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	n, err := io.Copy(&offsetWriter{w: w, offset: part.Offset, progress: progress}, io.LimitReader(resp.Body, part.Length))
+	if err == nil && n < part.Length {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// offsetWriter writes to w sequentially from offset.
+type offsetWriter struct {
+	w        io.WriterAt
+	offset   int64
+	progress func(n int64)
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.w.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	if w.progress != nil && n > 0 {
+		w.progress(int64(n))
+	}
+	return n, err
+}
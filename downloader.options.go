@@ -0,0 +1,34 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+// WithDownloaderPartSize sets the size of a part, used only when the block locations of the file are unavailable.
+func WithDownloaderPartSize(partSize int64) DownloaderOption {
+	return DownloaderOptionFunc(func(d *Downloader) {
+		d.partSize = partSize
+	})
+}
+
+// WithDownloaderConcurrency sets the max number of parts downloaded at the same time.
+func WithDownloaderConcurrency(concurrency int) DownloaderOption {
+	return DownloaderOptionFunc(func(d *Downloader) {
+		d.concurrency = concurrency
+	})
+}
+
+// WithDownloaderMaxRetries sets how many times a part failed transiently is tried again, 0 disables retry.
+func WithDownloaderMaxRetries(maxRetries int) DownloaderOption {
+	return DownloaderOptionFunc(func(d *Downloader) {
+		d.maxRetries = maxRetries
+	})
+}
+
+// WithDownloaderProgress sets the callback called with the bytes downloaded so far and the length of the file,
+// calls are serialized.
+func WithDownloaderProgress(progress func(downloaded, total int64)) DownloaderOption {
+	return DownloaderOptionFunc(func(d *Downloader) {
+		d.progress = progress
+	})
+}
@@ -0,0 +1,237 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"syscall"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+var (
+	_ io.ReadSeekCloser = (*File)(nil)
+	_ io.ReaderAt       = (*File)(nil)
+)
+
+// maxSeekSkip is the longest forward seek to be done by discarding bytes from the stream
+// instead of issuing a new OPEN.
+const maxSeekSkip = 64 << 10
+
+// File is an open regular file of FS, as returned by FS.Open, FS.Create and FS.OpenFile.
+// Content is read by OPEN, and written by APPEND.
+// Read reuses the stream of a ranged OPEN as long as reads are sequential, a new OPEN is issued
+// at the new offset only after a real Seek. ReadAt issues a ranged OPEN of its own and can be called concurrently.
+// HDFS files can only be written at the end, so every Write appends, as if opened with os.O_APPEND.
+type File struct {
+	fsys *FS
+	name string
+	flag int
+	info *FileStatus // status when opened
+
+	offset     int64         // offset for the next Read
+	body       io.ReadCloser // stream of OPEN, nil until first Read
+	bodyOffset int64         // offset of the next byte in body
+	closed     bool
+}
+
+// Name returns the name of the file as presented to FS.Open or FS.OpenFile.
+func (f *File) Name() string {
+	return f.name
+}
+
+// Stat returns a *FileStatusProperties describing the file.
+// The status of a file opened for writing is fetched again, as the length may have grown.
+func (f *File) Stat() (fs.FileInfo, error) {
+	if f.closed {
+		return nil, &fs.PathError{Op: "stat", Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.writable() {
+		info, err := f.fsys.stat(f.name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "stat", Path: f.name, Err: err}
+		}
+		f.info = info
+	}
+	return f.info, nil
+}
+
+// Read reads up to len(b) bytes from the File.
+// At end of file, Read returns 0, io.EOF.
+func (f *File) Read(b []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrClosed}
+	}
+	if !f.readable() {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: syscall.EBADF}
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+	if f.body != nil && f.bodyOffset != f.offset {
+		skip := f.offset - f.bodyOffset
+		if skip < 0 || skip > maxSeekSkip {
+			f.closeBody()
+		} else if n, err := io.CopyN(io.Discard, f.body, skip); err != nil {
+			f.bodyOffset += n
+			f.closeBody()
+		} else {
+			f.bodyOffset += n
+		}
+	}
+	if f.body == nil {
+		if f.info != nil && !f.writable() && f.offset >= f.info.Length {
+			return 0, io.EOF
+		}
+		body, err := f.open(f.offset, nil)
+		if err != nil {
+			return 0, &fs.PathError{Op: "read", Path: f.name, Err: err}
+		}
+		f.body = body
+		f.bodyOffset = f.offset
+	}
+	n, err := f.body.Read(b)
+	f.offset += int64(n)
+	f.bodyOffset += int64(n)
+	return n, err
+}
+
+// ReadAt reads len(b) bytes from the File starting at byte offset off by a ranged OPEN.
+// It returns the number of bytes read and the error, if any.
+// ReadAt always returns a non-nil error when n < len(b).
+// At end of file, that error is io.EOF.
+func (f *File) ReadAt(b []byte, off int64) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "readat", Path: f.name, Err: fs.ErrClosed}
+	}
+	if !f.readable() {
+		return 0, &fs.PathError{Op: "readat", Path: f.name, Err: syscall.EBADF}
+	}
+	if off < 0 {
+		return 0, &fs.PathError{Op: "readat", Path: f.name, Err: errors.New("negative offset")}
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+	if f.info != nil && !f.writable() && off >= f.info.Length {
+		return 0, io.EOF
+	}
+	body, err := f.open(off, types.Pointer(int64(len(b))))
+	if err != nil {
+		return 0, &fs.PathError{Op: "readat", Path: f.name, Err: err}
+	}
+	defer body.Close()
+	n, err := io.ReadFull(body, b)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Seek sets the offset for the next Read on file to offset, interpreted
+// according to whence: 0 means relative to the origin of the file, 1 means
+// relative to the current offset, and 2 means relative to the end.
+// It returns the new offset and an error, if any.
+// No request is issued by Seek, the stream is reopened by the next Read if the offset has really changed.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrClosed}
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		info, err := f.Stat()
+		if err != nil {
+			return 0, err
+		}
+		offset += info.Size()
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// open returns the content of the file from offset, length nil means up to the end of the file.
+func (f *File) open(offset int64, length *int64) (io.ReadCloser, error) {
+	req := &OpenRequest{
+		ProxyUser: f.fsys.c.ProxyUser(),
+		Path:      types.Pointer(f.fsys.hdfsPath(f.name)),
+		Length:    length,
+	}
+	if offset > 0 {
+		req.Offset = types.Pointer(offset)
+	}
+	resp, err := f.fsys.c.OpenWithContext(f.fsys.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (f *File) closeBody() error {
+	if f.body == nil {
+		return nil
+	}
+	err := f.body.Close()
+	f.body = nil
+	return err
+}
+
+// Write appends len(b) bytes to the File by a single APPEND.
+// It returns the number of bytes written and an error, if any.
+func (f *File) Write(b []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrClosed}
+	}
+	if !f.writable() {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: syscall.EBADF}
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+	resp, err := f.fsys.c.AppendWithContext(f.fsys.Context(), &AppendRequest{
+		ProxyUser:     f.fsys.c.ProxyUser(),
+		Path:          types.Pointer(f.fsys.hdfsPath(f.name)),
+		Body:          bytes.NewReader(b),
+		ContentLength: types.Pointer(int64(len(b))),
+	})
+	if err != nil {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: err}
+	}
+	defer resp.Body.Close()
+	return len(b), nil
+}
+
+// WriteString is like Write, but writes the contents of string s rather than a slice of bytes.
+func (f *File) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// Close closes the File, rendering it unusable for I/O.
+func (f *File) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return f.closeBody()
+}
+
+func (f *File) readable() bool {
+	return f.flag&(os.O_WRONLY|os.O_RDWR) != os.O_WRONLY
+}
+
+func (f *File) writable() bool {
+	return f.flag&(os.O_WRONLY|os.O_RDWR) != os.O_RDONLY
+}
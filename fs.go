@@ -0,0 +1,283 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+var (
+	_ fs.FS         = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.ReadFileFS = (*FS)(nil)
+	_ fs.GlobFS     = (*FS)(nil)
+)
+
+// FS provides access to the HDFS directory tree rooted at root.
+// FS implements fs.FS, fs.StatFS, fs.ReadDirFS, fs.ReadFileFS and fs.GlobFS,
+// names passed to its methods are unrooted, slash-separated paths relative to root, see fs.ValidPath.
+// The fs.FileInfo returned by FS is always a *FileStatusProperties.
+// The requests of FS are sent with its context, see WithContext.
+type FS struct {
+	c    *Client
+	root string
+	ctx  context.Context
+}
+
+// NewFS returns a file system for the tree of files rooted at the directory root.
+func NewFS(c *Client, root string) *FS {
+	return &FS{c: c, root: root}
+}
+
+// Client returns the client the file system is backed by.
+func (fsys *FS) Client() *Client {
+	return fsys.c
+}
+
+// Context returns the context the requests of the file system are sent with, context.Background if not set.
+func (fsys *FS) Context() context.Context {
+	if fsys.ctx != nil {
+		return fsys.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of the file system with its context changed to ctx,
+// so that the requests of the copy, and of the files opened by it, are cancelled once ctx is done,
+// as the methods of fs.FS take no context.
+func (fsys *FS) WithContext(ctx context.Context) *FS {
+	if ctx == nil {
+		panic("nil context")
+	}
+	fsys2 := *fsys
+	fsys2.ctx = ctx
+	return &fsys2
+}
+
+// hdfsPath returns the absolute HDFS path of name, which is assumed to be a valid fs path.
+func (fsys *FS) hdfsPath(name string) string {
+	return path.Join("/", fsys.root, name)
+}
+
+// Open opens the named file.
+// A regular file is read by OPEN, and a directory is listed by LISTSTATUS_BATCH.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	info, err := fsys.stat(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if info.IsDir() {
+		return &dirFile{fsys: fsys, name: name, info: info}, nil
+	}
+	return &File{fsys: fsys, name: name, flag: os.O_RDONLY, info: info}, nil
+}
+
+// Stat returns a *FileStatusProperties describing the named file.
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	info, err := fsys.stat(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return info, nil
+}
+
+// ReadDir reads the named directory and returns a list of directory entries sorted by filename.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dir, ok := f.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	list, err := dir.ReadDir(-1)
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list, err
+}
+
+// ReadFile reads the named file and returns its contents.
+func (fsys *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errors.New("is a directory")}
+	}
+	return io.ReadAll(f)
+}
+
+// Glob returns the names of all files matching pattern, with the syntax of path.Match.
+// Glob ignores file system errors such as I/O errors reading directories.
+// The only possible returned error is path.ErrBadPattern, reporting that the pattern is malformed.
+func (fsys *FS) Glob(pattern string) (matches []string, err error) {
+	// Check pattern is well-formed.
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	if !hasGlobMeta(pattern) {
+		if _, err = fsys.Stat(pattern); err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+
+	dir, file := path.Split(pattern)
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "" {
+		dir = "."
+	}
+
+	if !hasGlobMeta(dir) {
+		return fsys.glob(dir, file, nil)
+	}
+
+	// Prevent infinite recursion.
+	if dir == pattern {
+		return nil, path.ErrBadPattern
+	}
+
+	var dirs []string
+	dirs, err = fsys.Glob(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dirs {
+		matches, err = fsys.glob(d, file, matches)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// glob searches for files matching pattern in the directory dir and appends them to matches,
+// ignoring any I/O error listing dir.
+func (fsys *FS) glob(dir, pattern string, matches []string) ([]string, error) {
+	infos, err := fsys.ReadDir(dir)
+	if err != nil {
+		return matches, nil // ignore I/O error
+	}
+
+	for _, info := range infos {
+		n := info.Name()
+		matched, err := path.Match(pattern, n)
+		if err != nil {
+			return matches, err
+		}
+		if matched {
+			matches = append(matches, path.Join(dir, n))
+		}
+	}
+	return matches, nil
+}
+
+// hasGlobMeta reports whether path contains any of the magic characters recognized by path.Match.
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
+}
+
+func (fsys *FS) stat(name string) (*FileStatus, error) {
+	return fsys.c.fileStatus(fsys.Context(), fsys.hdfsPath(name))
+}
+
+// dirFile is a directory opened by FS, its entries are listed batch by batch.
+type dirFile struct {
+	fsys *FS
+	name string
+	info *FileStatus
+
+	entries    []fs.DirEntry // entries fetched but not returned yet
+	startAfter *string       // pathSuffix of the last entry fetched
+	eof        bool          // no more entries on the namenode
+	closed     bool
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *dirFile) Close() error {
+	if d.closed {
+		return &fs.PathError{Op: "close", Path: d.name, Err: fs.ErrClosed}
+	}
+	d.closed = true
+	return nil
+}
+
+// ReadDir reads the contents of the directory, see fs.ReadDirFile.
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: fs.ErrClosed}
+	}
+	for !d.eof && (n <= 0 || len(d.entries) < n) {
+		if err := d.fetch(); err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: err}
+		}
+	}
+	if n <= 0 {
+		list := d.entries
+		d.entries = nil
+		return list, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	list := d.entries[:n:n]
+	d.entries = d.entries[n:]
+	return list, nil
+}
+
+// fetch lists the next batch of the directory.
+func (d *dirFile) fetch() error {
+	statuses, more, err := d.fsys.c.listDirBatch(d.fsys.Context(), d.fsys.hdfsPath(d.name), d.startAfter)
+	if err != nil {
+		return err
+	}
+	for i := range statuses {
+		d.entries = append(d.entries, fs.FileInfoToDirEntry(&statuses[i]))
+	}
+	if len(statuses) > 0 {
+		d.startAfter = types.Pointer(statuses[len(statuses)-1].PathSuffix)
+	}
+	d.eof = !more
+	return nil
+}
@@ -0,0 +1,288 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/searKing/golang/go/exp/types"
+	time_ "github.com/searKing/golang/go/time"
+)
+
+// Create creates or truncates the named file, like os.Create.
+// If the file does not exist, it is created with DefaultPermissionFile.
+// The returned File is opened with os.O_RDWR.
+func (fsys *FS) Create(name string) (*File, error) {
+	return fsys.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, fs.FileMode(DefaultPermissionFile))
+}
+
+// OpenFile is the generalized open call, like os.OpenFile.
+// The flags are mapped onto HDFS as below, perm is used only when the file is created:
+//
+//	os.O_CREATE|os.O_EXCL: CREATE without overwrite, fails with fs.ErrExist if the file exists.
+//	os.O_CREATE|os.O_TRUNC: CREATE with overwrite.
+//	os.O_CREATE: CREATE if the file does not exist.
+//	os.O_TRUNC: TRUNCATE to zero length.
+//	os.O_APPEND: APPEND on every Write.
+//
+// As HDFS supports no random write, opening an existing file for writing requires either os.O_APPEND or os.O_TRUNC.
+func (fsys *FS) OpenFile(name string, flag int, perm fs.FileMode) (*File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if flag&(os.O_WRONLY|os.O_RDWR) == os.O_RDONLY {
+		info, err := fsys.stat(name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		if info.IsDir() {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		}
+		return &File{fsys: fsys, name: name, flag: flag, info: info}, nil
+	}
+
+	switch {
+	case flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		if err := fsys.create(name, false, perm); err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+	case flag&os.O_CREATE != 0 && flag&os.O_TRUNC != 0:
+		if err := fsys.create(name, true, perm); err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+	default:
+		info, err := fsys.stat(name)
+		if err != nil {
+			if flag&os.O_CREATE == 0 || !errors.Is(err, fs.ErrNotExist) {
+				return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+			}
+			if err := fsys.create(name, false, perm); err != nil {
+				return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+			}
+			break
+		}
+		if info.IsDir() {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		}
+		if flag&os.O_TRUNC != 0 {
+			if info.Length > 0 {
+				if err := fsys.truncate(name, 0); err != nil {
+					return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+				}
+			}
+			break
+		}
+		if flag&os.O_APPEND == 0 {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: errors.New("random write is not supported, open with O_APPEND or O_TRUNC")}
+		}
+	}
+	return &File{fsys: fsys, name: name, flag: flag}, nil
+}
+
+// MkdirAll creates a directory named path, along with any necessary parents, like os.MkdirAll.
+// If path is already a directory, MkdirAll does nothing and returns nil.
+func (fsys *FS) MkdirAll(path string, perm fs.FileMode) error {
+	if !fs.ValidPath(path) {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: fs.ErrInvalid}
+	}
+	resp, err := fsys.c.MkdirsWithContext(fsys.Context(), &MkdirsRequest{
+		ProxyUser:  fsys.c.ProxyUser(),
+		Path:       types.Pointer(fsys.hdfsPath(path)),
+		Permission: types.Pointer(int(permissionFromFileMode(perm))),
+	})
+	if err != nil {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: err}
+	}
+	defer resp.Body.Close()
+	if !resp.Boolean {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: syscall.ENOTDIR}
+	}
+	return nil
+}
+
+// Remove removes the named file or (empty) directory, like os.Remove.
+func (fsys *FS) Remove(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	// DELETE reports no error for a missing file.
+	if _, err := fsys.stat(name); err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: err}
+	}
+	if err := fsys.delete(name, false); err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: err}
+	}
+	return nil
+}
+
+// RemoveAll removes path and any children it contains, like os.RemoveAll.
+// If the path does not exist, RemoveAll returns nil (no error).
+func (fsys *FS) RemoveAll(path string) error {
+	if !fs.ValidPath(path) || path == "." {
+		return &fs.PathError{Op: "removeall", Path: path, Err: fs.ErrInvalid}
+	}
+	if err := fsys.delete(path, true); err != nil {
+		return &fs.PathError{Op: "removeall", Path: path, Err: err}
+	}
+	return nil
+}
+
+// Rename renames (moves) oldpath to newpath, like os.Rename, and returns a *os.LinkError on failure.
+// Unlike os.Rename, RENAME never replaces an existing newpath.
+func (fsys *FS) Rename(oldpath, newpath string) error {
+	if !fs.ValidPath(oldpath) || !fs.ValidPath(newpath) {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrInvalid}
+	}
+	resp, err := fsys.c.RenameWithContext(fsys.Context(), &RenameRequest{
+		ProxyUser:   fsys.c.ProxyUser(),
+		Path:        types.Pointer(fsys.hdfsPath(oldpath)),
+		Destination: types.Pointer(fsys.hdfsPath(newpath)),
+	})
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.Boolean {
+		return nil
+	}
+
+	// RENAME tells nothing but false, find out why.
+	if _, err := fsys.stat(oldpath); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+	if _, err := fsys.stat(newpath); err == nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrExist}
+	}
+	return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errors.New("rename failed")}
+}
+
+// Chmod changes the mode of the named file to mode, like os.Chmod.
+// Only the permission bits and os.ModeSticky are used.
+func (fsys *FS) Chmod(name string, mode fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrInvalid}
+	}
+	resp, err := fsys.c.SetPermissionWithContext(fsys.Context(), &SetPermissionRequest{
+		ProxyUser:  fsys.c.ProxyUser(),
+		Path:       types.Pointer(fsys.hdfsPath(name)),
+		Permission: permissionFromFileMode(mode).New(),
+	})
+	if err != nil {
+		return &fs.PathError{Op: "chmod", Path: name, Err: err}
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// Chown changes the owner and group of the named file, like os.Chown.
+// HDFS identifies users and groups by name rather than by numeric id,
+// an empty owner or group leaves it unchanged.
+func (fsys *FS) Chown(name string, owner, group string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "chown", Path: name, Err: fs.ErrInvalid}
+	}
+	req := &SetOwnerRequest{
+		ProxyUser: fsys.c.ProxyUser(),
+		Path:      types.Pointer(fsys.hdfsPath(name)),
+	}
+	if owner != "" {
+		req.Owner = types.Pointer(owner)
+	}
+	if group != "" {
+		req.Group = types.Pointer(group)
+	}
+	resp, err := fsys.c.SetOwnerWithContext(fsys.Context(), req)
+	if err != nil {
+		return &fs.PathError{Op: "chown", Path: name, Err: err}
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// Chtimes changes the access and modification times of the named file, like os.Chtimes.
+// A zero time.Time value will leave the corresponding file time unchanged.
+func (fsys *FS) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: fs.ErrInvalid}
+	}
+	req := &SetTimesRequest{
+		ProxyUser: fsys.c.ProxyUser(),
+		Path:      types.Pointer(fsys.hdfsPath(name)),
+	}
+	if !atime.IsZero() {
+		req.Accesstime = &time_.UnixTimeMillisecond{Time: atime}
+	}
+	if !mtime.IsZero() {
+		req.Modificationtime = &time_.UnixTimeMillisecond{Time: mtime}
+	}
+	resp, err := fsys.c.SetTimesWithContext(fsys.Context(), req)
+	if err != nil {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: err}
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+func (fsys *FS) create(name string, overwrite bool, perm fs.FileMode) error {
+	resp, err := fsys.c.CreateWithContext(fsys.Context(), &CreateRequest{
+		ProxyUser:  fsys.c.ProxyUser(),
+		Path:       types.Pointer(fsys.hdfsPath(name)),
+		Overwrite:  types.Pointer(overwrite),
+		Permission: types.Pointer(int(permissionFromFileMode(perm))),
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+func (fsys *FS) truncate(name string, size int64) error {
+	resp, err := fsys.c.TruncateWithContext(fsys.Context(), &TruncateRequest{
+		ProxyUser: fsys.c.ProxyUser(),
+		Path:      types.Pointer(fsys.hdfsPath(name)),
+		NewLength: types.Pointer(size),
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+func (fsys *FS) delete(name string, recursive bool) error {
+	resp, err := fsys.c.DeleteWithContext(fsys.Context(), &DeleteRequest{
+		ProxyUser: fsys.c.ProxyUser(),
+		Path:      types.Pointer(fsys.hdfsPath(name)),
+		Recursive: types.Pointer(recursive),
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// permissionFromFileMode returns the HDFS permission of mode, keeping the sticky bit.
+func permissionFromFileMode(mode fs.FileMode) Permission {
+	perm := Permission(mode.Perm())
+	if mode&fs.ModeSticky != 0 {
+		perm |= PermissionSticky
+	}
+	return perm
+}
+
+// fileMode returns the fs.FileMode of the permission, keeping the sticky bit.
+func (p Permission) fileMode() fs.FileMode {
+	mode := fs.FileMode(p).Perm()
+	if p&PermissionSticky != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
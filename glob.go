@@ -0,0 +1,340 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GlobPattern is a glob pattern matching a single path component, with the semantics of GlobPattern of Hadoop:
+//
+//   - '*' matches any sequence of characters, including the empty one.
+//   - '?' matches any single character.
+//   - '[abc]' matches a single character in the set, ranges like '[a-z]' are allowed.
+//   - '[^abc]' matches a single character not in the set, so does '[!abc]', '^' or '!' elsewhere in a set is a literal.
+//   - '{ab,cd}' matches any of the comma separated alternatives, which may be nested.
+//   - '\c' matches character c literally.
+//
+// Unlike path.Match, files whose names begin with a dot are matched by * and ?.
+// See: https://hadoop.apache.org/docs/current/api/org/apache/hadoop/fs/FileSystem.html#globStatus-org.apache.hadoop.fs.Path-
+type GlobPattern struct {
+	glob        string
+	re          *regexp.Regexp
+	hasWildcard bool
+}
+
+// CompileGlobPattern parses a glob pattern, an error wrapping path.ErrBadPattern is returned if malformed.
+func CompileGlobPattern(glob string) (*GlobPattern, error) {
+	var re strings.Builder
+	var hasWildcard bool
+	var setOpen bool
+	var curlyOpen int
+	badPattern := func(msg string, pos int) error {
+		return fmt.Errorf("%w: %s at %d of %q", path.ErrBadPattern, msg, pos, glob)
+	}
+
+	re.WriteString(`^(?s:`)
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '\\':
+			i++
+			if i >= len(glob) {
+				return nil, badPattern("missing escaped character", i)
+			}
+			re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case setOpen:
+			switch c {
+			case ']':
+				setOpen = false
+				re.WriteByte(c)
+			case '^', '!':
+				if glob[i-1] == '[' {
+					re.WriteByte('^')
+				} else {
+					re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+				}
+			case '-':
+				re.WriteByte(c)
+			case '[':
+				return nil, badPattern("unclosed character class", i)
+			default:
+				re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			}
+		case c == '*':
+			re.WriteString(`.*`)
+			hasWildcard = true
+		case c == '?':
+			re.WriteByte('.')
+			hasWildcard = true
+		case c == '[':
+			setOpen = true
+			hasWildcard = true
+			re.WriteByte(c)
+		case c == '{':
+			curlyOpen++
+			hasWildcard = true
+			re.WriteString(`(?:`)
+		case c == ',' && curlyOpen > 0:
+			re.WriteByte('|')
+		case c == '}' && curlyOpen > 0:
+			curlyOpen--
+			re.WriteByte(')')
+		default:
+			re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	if setOpen {
+		return nil, badPattern("unclosed character class", len(glob))
+	}
+	if curlyOpen > 0 {
+		return nil, badPattern("unclosed group", len(glob))
+	}
+	re.WriteString(`)$`)
+
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		return nil, badPattern(err.Error(), 0)
+	}
+	return &GlobPattern{glob: glob, re: compiled, hasWildcard: hasWildcard}, nil
+}
+
+// Match reports whether name matches the pattern.
+func (p *GlobPattern) Match(name string) bool {
+	return p.re.MatchString(name)
+}
+
+// HasWildcard reports whether the pattern has any special character but escaping,
+// a pattern without wildcard matches only the name of itself unescaped.
+func (p *GlobPattern) HasWildcard() bool {
+	return p.hasWildcard
+}
+
+// String returns the source text of the pattern.
+func (p *GlobPattern) String() string {
+	return p.glob
+}
+
+// Glob returns the status of all files matching pattern, sorted by path, with the semantics of globStatus of Hadoop.
+// The pattern is matched one path component at a time, see GlobPattern for the syntax of a component,
+// and an alternation spanning components, such as {a/b,c}, is expanded into separate patterns first.
+// Only directories matching a component are listed, by LISTSTATUS_BATCH, for the next component,
+// and a component without wildcard is never listed, but looked up by GETFILESTATUS at last.
+// The PathPrefix of a status returned is the directory containing the file, and PathSuffix is its name.
+//
+// Glob returns no error but an empty result if no file matches pattern.
+// An error wrapping path.ErrBadPattern is returned if the pattern is malformed.
+func (c *Client) Glob(ctx context.Context, pattern string) ([]FileStatus, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	patterns, err := expandGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var matches []FileStatus
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		found, err := c.glob(ctx, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, st := range found {
+			name := path.Join(st.PathPrefix, st.PathSuffix)
+			if !seen[name] {
+				seen[name] = true
+				matches = append(matches, st)
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return path.Join(matches[i].PathPrefix, matches[i].PathSuffix) < path.Join(matches[j].PathPrefix, matches[j].PathSuffix)
+	})
+	return matches, nil
+}
+
+// globCandidate is a path matching the components of a pattern so far.
+type globCandidate struct {
+	path   string
+	status *FileStatus // nil if not listed, with components without wildcard only
+}
+
+// glob returns the status of files matching pattern, which has no alternation spanning components.
+func (c *Client) glob(ctx context.Context, pattern string) ([]FileStatus, error) {
+	var components []string
+	for _, component := range strings.Split(pattern, "/") {
+		if component != "" && component != "." {
+			components = append(components, component)
+		}
+	}
+
+	candidates := []globCandidate{{path: "/"}}
+	for i, component := range components {
+		last := i == len(components)-1
+		p, err := CompileGlobPattern(component)
+		if err != nil {
+			return nil, err
+		}
+		if !p.HasWildcard() {
+			name := unescapeGlob(component)
+			for j := range candidates {
+				candidates[j] = globCandidate{path: path.Join(candidates[j].path, name)}
+			}
+			continue
+		}
+
+		var next []globCandidate
+		for _, candidate := range candidates {
+			if candidate.status != nil && !candidate.status.IsDir() {
+				continue
+			}
+			children, err := c.ListAll(ctx, candidate.path)
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return nil, err
+			}
+			for k := range children {
+				child := &children[k]
+				// a file is listed as itself with an empty suffix
+				if child.PathSuffix == "" || (!last && !child.IsDir()) {
+					continue
+				}
+				if p.Match(child.PathSuffix) {
+					next = append(next, globCandidate{path: path.Join(candidate.path, child.PathSuffix), status: child})
+				}
+			}
+		}
+		candidates = next
+		if len(candidates) == 0 {
+			return nil, nil
+		}
+	}
+
+	matches := make([]FileStatus, 0, len(candidates))
+	for _, candidate := range candidates {
+		st := candidate.status
+		if st == nil {
+			var err error
+			st, err = c.fileStatus(ctx, candidate.path)
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return nil, err
+			}
+			st.PathPrefix, st.PathSuffix = path.Split(candidate.path)
+			if st.PathSuffix != "" {
+				st.PathPrefix = path.Clean(st.PathPrefix)
+			}
+		}
+		matches = append(matches, *st)
+	}
+	return matches, nil
+}
+
+// unescapeGlob removes the escaping backslashes of glob without wildcard.
+func unescapeGlob(glob string) string {
+	if !strings.Contains(glob, `\`) {
+		return glob
+	}
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		if glob[i] == '\\' && i+1 < len(glob) {
+			i++
+		}
+		b.WriteByte(glob[i])
+	}
+	return b.String()
+}
+
+// expandGlob expands the alternations of pattern spanning path components into separate patterns,
+// as GlobExpander of Hadoop, for the pattern to be matched one component at a time.
+// An alternation within a component is left as is.
+func expandGlob(pattern string) ([]string, error) {
+	open, close, err := findSlashGroup(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if open < 0 {
+		return []string{pattern}, nil
+	}
+	var patterns []string
+	for _, alt := range splitGroup(pattern[open+1 : close]) {
+		expanded, err := expandGlob(pattern[:open] + alt + pattern[close+1:])
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, expanded...)
+	}
+	return patterns, nil
+}
+
+// findSlashGroup returns the offsets of the braces of the first outermost group of pattern containing a slash,
+// or -1 if none.
+func findSlashGroup(pattern string) (open, close int, err error) {
+	open = -1
+	depth := 0
+	hasSlash := false
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			if depth == 0 {
+				open = i
+				hasSlash = false
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 && hasSlash {
+				return open, i, nil
+			}
+		case '/':
+			if depth > 0 {
+				hasSlash = true
+			}
+		}
+	}
+	if depth > 0 {
+		return -1, -1, fmt.Errorf("%w: unclosed group of %q", path.ErrBadPattern, pattern)
+	}
+	return -1, -1, nil
+}
+
+// splitGroup splits the content of a group by the commas outside any nested group.
+func splitGroup(group string) []string {
+	var alts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(group); i++ {
+		switch group[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alts = append(alts, group[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(alts, group[start:])
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"errors"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompileGlobPattern(t *testing.T) {
+	tests := []struct {
+		glob     string
+		match    []string
+		mismatch []string
+		wildcard bool
+	}{
+		{glob: "{2024,2025}", match: []string{"2024", "2025"}, mismatch: []string{"2023", "20245", "{2024,2025}"}, wildcard: true},
+		{glob: "*", match: []string{"01", ".hidden", ""}, wildcard: true},
+		{glob: "part-[0-9]*", match: []string{"part-0", "part-00000", "part-9.gz"}, mismatch: []string{"part-", "part-x0", "_SUCCESS"}, wildcard: true},
+		{glob: "part-[^0]*", match: []string{"part-1", "part-!"}, mismatch: []string{"part-0", "part-00000"}, wildcard: true},
+		{glob: "part-[!0]*", match: []string{"part-1", "part-!"}, mismatch: []string{"part-0", "part-00000"}, wildcard: true},
+		{glob: "part-[a!]", match: []string{"part-a", "part-!"}, mismatch: []string{"part-b"}, wildcard: true},
+		{glob: "part-[a^]", match: []string{"part-a", "part-^"}, mismatch: []string{"part-b"}, wildcard: true},
+		{glob: "{a,{b,c}}", match: []string{"a", "b", "c"}, mismatch: []string{"d", "bc", "{a,{b,c}}"}, wildcard: true},
+		{glob: "x{a,b{c,d}}y", match: []string{"xay", "xbcy", "xbdy"}, mismatch: []string{"xby", "xy"}, wildcard: true},
+		{glob: "file?.txt", match: []string{"file1.txt", "file..txt"}, mismatch: []string{"file.txt", "file12.txt"}, wildcard: true},
+		{glob: `\*`, match: []string{"*"}, mismatch: []string{"a", `\*`}},
+		{glob: `\{a,b\}`, match: []string{"{a,b}"}, mismatch: []string{"a", "b"}},
+		{glob: `a\[b`, match: []string{"a[b"}, mismatch: []string{"ab"}},
+		{glob: "a.b+c(d)", match: []string{"a.b+c(d)"}, mismatch: []string{"aXb+c(d)"}},
+		{glob: "a,b}", match: []string{"a,b}"}},
+	}
+	for _, tt := range tests {
+		p, err := CompileGlobPattern(tt.glob)
+		if err != nil {
+			t.Errorf("CompileGlobPattern(%q) failed: %s", tt.glob, err)
+			continue
+		}
+		for _, name := range tt.match {
+			if !p.Match(name) {
+				t.Errorf("%q Match(%q): got false, want true", tt.glob, name)
+			}
+		}
+		for _, name := range tt.mismatch {
+			if p.Match(name) {
+				t.Errorf("%q Match(%q): got true, want false", tt.glob, name)
+			}
+		}
+		if p.HasWildcard() != tt.wildcard {
+			t.Errorf("%q HasWildcard(): got %t, want %t", tt.glob, p.HasWildcard(), tt.wildcard)
+		}
+		if p.String() != tt.glob {
+			t.Errorf("String(): got %q, want %q", p.String(), tt.glob)
+		}
+	}
+}
+
+func TestCompileGlobPattern_Bad(t *testing.T) {
+	for _, glob := range []string{"part-[0-9", "[", "{a,b", "{a,{b,c}", `a\`, "[a-]z[", "a[b[c]"} {
+		if _, err := CompileGlobPattern(glob); !errors.Is(err, path.ErrBadPattern) {
+			t.Errorf("CompileGlobPattern(%q): got %v, want %v", glob, err, path.ErrBadPattern)
+		}
+	}
+}
+
+func TestCompileGlobPattern_Components(t *testing.T) {
+	// matched one component at a time, as Glob does
+	pattern := "/logs/{2024,2025}/*/part-[0-9]*"
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"/logs/2024/01/part-00000", true},
+		{"/logs/2025/12/part-1.gz", true},
+		{"/logs/2025/.tmp/part-00000", true},
+		{"/logs/2023/01/part-00000", false},
+		{"/logs/2024/01/_SUCCESS", false},
+		{"/logs/2024/part-00000", false},
+		{"/logs/2024/01/02/part-00000", false},
+	}
+	globs := strings.Split(pattern, "/")
+	for _, tt := range tests {
+		names := strings.Split(tt.name, "/")
+		got := len(names) == len(globs)
+		for i := 0; got && i < len(globs); i++ {
+			p, err := CompileGlobPattern(globs[i])
+			if err != nil {
+				t.Fatalf("CompileGlobPattern(%q) failed: %s", globs[i], err)
+			}
+			got = p.Match(names[i])
+		}
+		if got != tt.want {
+			t.Errorf("%q matches %q: got %t, want %t", pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpandGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"/logs/{2024,2025}/*/part-[0-9]*", []string{"/logs/{2024,2025}/*/part-[0-9]*"}},
+		{"/logs/{2024/01,2025}/part-*", []string{"/logs/2024/01/part-*", "/logs/2025/part-*"}},
+		{"{a,{b/c,d}}/e", []string{"a/e", "b/c/e", "d/e"}},
+		{"{a/b,c}/{d/e,f}", []string{"a/b/d/e", "a/b/f", "c/d/e", "c/f"}},
+		{"/x/{a,{b,c}}/y", []string{"/x/{a,{b,c}}/y"}},
+		{`/x/\{a/b,c\}`, []string{`/x/\{a/b,c\}`}},
+		{"/plain/path", []string{"/plain/path"}},
+	}
+	for _, tt := range tests {
+		got, err := expandGlob(tt.pattern)
+		if err != nil {
+			t.Errorf("expandGlob(%q) failed: %s", tt.pattern, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandGlob(%q): got %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+
+	for _, pattern := range []string{"/logs/{2024/01,2025", "{a,{b/c,d}/e"} {
+		if _, err := expandGlob(pattern); !errors.Is(err, path.ErrBadPattern) {
+			t.Errorf("expandGlob(%q): got %v, want %v", pattern, err, path.ErrBadPattern)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+// ListStatusBatchPaginator lists a directory page by page by LISTSTATUS_BATCH,
+// following RemainingEntries and sending the pathSuffix of the last entry got as startAfter.
+type ListStatusBatchPaginator struct {
+	c   *Client
+	req ListStatusBatchRequest
+
+	firstPage bool
+	more      bool
+}
+
+// NewListStatusBatchPaginator returns a paginator listing the directory of req,
+// req.StartAfter, if set, is where the first page starts after.
+func NewListStatusBatchPaginator(c *Client, req *ListStatusBatchRequest) *ListStatusBatchPaginator {
+	return &ListStatusBatchPaginator{c: c, req: *req, firstPage: true}
+}
+
+// HasNext reports whether more pages are available.
+func (p *ListStatusBatchPaginator) HasNext() bool {
+	return p.firstPage || p.more
+}
+
+// Next returns the next page of entries, sorted by name, with PathPrefix filled in as the directory.
+// An empty page is returned with no error once all pages are got.
+func (p *ListStatusBatchPaginator) Next(ctx context.Context) ([]FileStatus, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	if !p.HasNext() {
+		return nil, nil
+	}
+	statuses, more, err := p.c.listStatusBatchPage(ctx, &p.req)
+	if err != nil {
+		return nil, err
+	}
+	p.firstPage = false
+	p.more = more
+	if len(statuses) > 0 {
+		p.req.StartAfter = types.Pointer(statuses[len(statuses)-1].PathSuffix)
+	}
+	return statuses, nil
+}
+
+// ListStatusIterator iterates over the entries of a directory, fetched page by page as needed.
+// Successive calls to the Next method step through the entries, like bufio.Scanner:
+//
+//	it := c.NewListStatusIterator(ctx, req)
+//	for it.Next() {
+//		fmt.Println(it.FileStatus().Name())
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type ListStatusIterator struct {
+	ctx       context.Context
+	paginator *ListStatusBatchPaginator
+
+	page []FileStatus
+	cur  *FileStatus
+	err  error
+}
+
+// NewListStatusIterator returns an iterator over the entries of the directory of req.
+// The iteration is stopped once ctx is done.
+func (c *Client) NewListStatusIterator(ctx context.Context, req *ListStatusBatchRequest) *ListStatusIterator {
+	if ctx == nil {
+		panic("nil context")
+	}
+	return &ListStatusIterator{ctx: ctx, paginator: NewListStatusBatchPaginator(c, req)}
+}
+
+// Next advances the iterator to the next entry, which will then be available through the FileStatus method.
+// It returns false when the iteration stops, either by reaching the end of the directory or an error.
+func (it *ListStatusIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || !it.paginator.HasNext() {
+			it.cur = nil
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			continue
+		}
+		it.page, it.err = it.paginator.Next(it.ctx)
+	}
+	it.cur = &it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// FileStatus returns the current entry.
+func (it *ListStatusIterator) FileStatus() *FileStatus {
+	return it.cur
+}
+
+// Err returns the first error that was encountered by the iterator.
+func (it *ListStatusIterator) Err() error {
+	return it.err
+}
+
+// ListAll returns all entries of the directory path, listed page by page by LISTSTATUS_BATCH.
+func (c *Client) ListAll(ctx context.Context, path string) ([]FileStatus, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	p := NewListStatusBatchPaginator(c, &ListStatusBatchRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+	})
+	var all []FileStatus
+	for p.HasNext() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		statuses, err := p.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, statuses...)
+	}
+	return all, nil
+}
+
+// listDirBatch lists a batch of the directory dir after the entry named startAfter, nil for the first batch.
+// See listStatusBatchPage.
+func (c *Client) listDirBatch(ctx context.Context, dir string, startAfter *string) (statuses []FileStatus, more bool, err error) {
+	return c.listStatusBatchPage(ctx, &ListStatusBatchRequest{
+		ProxyUser:  c.ProxyUser(),
+		Path:       types.Pointer(dir),
+		StartAfter: startAfter,
+	})
+}
+
+// listStatusBatchPage lists a batch of the directory of req.
+// Entries returned are sorted by name, with PathPrefix filled in as the directory.
+// more reports whether entries remain after the batch.
+func (c *Client) listStatusBatchPage(ctx context.Context, req *ListStatusBatchRequest) (statuses []FileStatus, more bool, err error) {
+	resp, err := c.ListStatusBatchWithContext(ctx, req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	statuses = resp.DirectoryListing.PartialListing.FileStatuses.FileStatus
+	// entries are sorted by name, a batch not after startAfter would never come to an end,
+	// as with a server ignoring startAfter.
+	if req.StartAfter != nil && len(statuses) > 0 && statuses[0].PathSuffix <= *req.StartAfter {
+		return nil, false, fmt.Errorf("list status batch did not advance past %q", *req.StartAfter)
+	}
+	dir := types.Value(req.Path)
+	for i := range statuses {
+		statuses[i].PathPrefix = dir
+	}
+	return statuses, resp.DirectoryListing.RemainingEntries > 0 && len(statuses) > 0, nil
+}
+
+// GetSnapshotDiffListingPaginator lists the diff between two snapshots page by page by GETSNAPSHOTDIFFLISTING,
+// sending LastPath and LastIndex of the page got as snapshotdiffstartpath and snapshotdiffindex,
+// until both are reset, as DistributedFileSystem of Hadoop does.
+type GetSnapshotDiffListingPaginator struct {
+	c   *Client
+	req GetSnapshotDiffListingRequest
+
+	firstPage bool
+	done      bool
+}
+
+// NewGetSnapshotDiffListingPaginator returns a paginator listing the diff of req,
+// req.Snapshotdiffstartpath and req.Snapshotdiffindex, if set, are where the first page starts from.
+func NewGetSnapshotDiffListingPaginator(c *Client, req *GetSnapshotDiffListingRequest) *GetSnapshotDiffListingPaginator {
+	return &GetSnapshotDiffListingPaginator{c: c, req: *req, firstPage: true}
+}
+
+// HasNext reports whether more pages are available.
+func (p *GetSnapshotDiffListingPaginator) HasNext() bool {
+	return p.firstPage || !p.done
+}
+
+// Next returns the next page of the diff.
+// A nil page is returned with no error once all pages are got.
+func (p *GetSnapshotDiffListingPaginator) Next(ctx context.Context) (*SnapshotDiffReportListing, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	if !p.HasNext() {
+		return nil, nil
+	}
+	resp, err := p.c.GetSnapshotDiffListingWithContext(ctx, &p.req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	listing := resp.SnapshotDiffReportListing
+	// a page ending where it started would never come to an end, as with a server ignoring the start.
+	if !p.firstPage && !listing.Done() &&
+		listing.LastPath == types.Value(p.req.Snapshotdiffstartpath) && listing.LastIndex == types.Value(p.req.Snapshotdiffindex) {
+		return nil, fmt.Errorf("get snapshot diff listing did not advance past %q at %d", listing.LastPath, listing.LastIndex)
+	}
+	p.firstPage = false
+	p.done = listing.Done()
+	p.req.Snapshotdiffstartpath = types.Pointer(listing.LastPath)
+	p.req.Snapshotdiffindex = types.Pointer(listing.LastIndex)
+	return &listing, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/searKing/golang/go/exp/types"
+
+	"github.com/searKing/webhdfs"
+)
+
+// newSnapshotDiffListingServer returns a client of a namenode answering GETSNAPSHOTDIFFLISTING by page,
+// and the list of the snapshotdiffstartpath and snapshotdiffindex of each request, "" and "" if not sent.
+func newSnapshotDiffListingServer(t *testing.T, page func(startPath, index string) webhdfs.SnapshotDiffReportListing) (*webhdfs.Client, *[][2]string) {
+	var requests [][2]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("op") != webhdfs.OpGetSnapshotDiffListing || r.URL.Path != "/webhdfs/v1/dir" {
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		startPath, index := q.Get("snapshotdiffstartpath"), q.Get("snapshotdiffindex")
+		requests = append(requests, [2]string{startPath, index})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"SnapshotDiffReportListing": page(startPath, index)})
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := webhdfs.New(strings.TrimPrefix(srv.URL, "http://"), webhdfs.WithDisableSSL(true), webhdfs.WithKerberosConfig(nil))
+	if err != nil {
+		t.Fatalf("create client %s", err)
+	}
+	return c, &requests
+}
+
+func newSnapshotDiffListingRequest() *webhdfs.GetSnapshotDiffListingRequest {
+	return &webhdfs.GetSnapshotDiffListingRequest{
+		Path:            types.Pointer("dir"),
+		Oldsnapshotname: types.Pointer("s0"),
+		Snapshotname:    types.Pointer("s1"),
+	}
+}
+
+func TestGetSnapshotDiffListingPaginator(t *testing.T) {
+	c, requests := newSnapshotDiffListingServer(t, func(startPath, index string) webhdfs.SnapshotDiffReportListing {
+		switch {
+		case startPath == "" && index == "":
+			return webhdfs.SnapshotDiffReportListing{
+				CreateList: []webhdfs.DiffReportListingEntry{{DirId: 1, FileId: 2, SourcePath: "/a/1.txt"}},
+				LastPath:   "/a",
+				LastIndex:  1,
+			}
+		case startPath == "/a" && index == "1":
+			return webhdfs.SnapshotDiffReportListing{
+				DeleteList: []webhdfs.DiffReportListingEntry{{DirId: 3, FileId: 4, SourcePath: "/b/2.txt"}},
+				LastPath:   "",
+				LastIndex:  -1,
+			}
+		}
+		t.Errorf("unexpected page of %q at %s", startPath, index)
+		return webhdfs.SnapshotDiffReportListing{LastIndex: -1}
+	})
+
+	p := webhdfs.NewGetSnapshotDiffListingPaginator(c, newSnapshotDiffListingRequest())
+	var got []string
+	for p.HasNext() {
+		listing, err := p.Next(context.Background())
+		if err != nil {
+			t.Fatalf("webhdfs GetSnapshotDiffListing failed: %s", err)
+		}
+		for _, e := range listing.CreateList {
+			got = append(got, "+"+e.SourcePath)
+		}
+		for _, e := range listing.DeleteList {
+			got = append(got, "-"+e.SourcePath)
+		}
+	}
+	if want := []string{"+/a/1.txt", "-/b/2.txt"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("GetSnapshotDiffListingPaginator: got %q, want %q", got, want)
+	}
+	if want := [][2]string{{"", ""}, {"/a", "1"}}; len(*requests) != len(want) || (*requests)[0] != want[0] || (*requests)[1] != want[1] {
+		t.Errorf("GetSnapshotDiffListingPaginator requests: got %q, want %q", *requests, want)
+	}
+
+	// no more pages
+	listing, err := p.Next(context.Background())
+	if listing != nil || err != nil {
+		t.Errorf("GetSnapshotDiffListingPaginator Next after done: got %v, %v, want nil, nil", listing, err)
+	}
+	if len(*requests) != 2 {
+		t.Errorf("GetSnapshotDiffListingPaginator Next after done sent a request")
+	}
+}
+
+func TestGetSnapshotDiffListingPaginator_NotAdvance(t *testing.T) {
+	// a server ignoring the start returns the first page again and again
+	c, requests := newSnapshotDiffListingServer(t, func(startPath, index string) webhdfs.SnapshotDiffReportListing {
+		return webhdfs.SnapshotDiffReportListing{
+			CreateList: []webhdfs.DiffReportListingEntry{{DirId: 1, FileId: 2, SourcePath: "/a/1.txt"}},
+			LastPath:   "/a",
+			LastIndex:  1,
+		}
+	})
+
+	p := webhdfs.NewGetSnapshotDiffListingPaginator(c, newSnapshotDiffListingRequest())
+	if _, err := p.Next(context.Background()); err != nil {
+		t.Fatalf("webhdfs GetSnapshotDiffListing failed: %s", err)
+	}
+	if !p.HasNext() {
+		t.Fatalf("GetSnapshotDiffListingPaginator HasNext: got false, want true")
+	}
+	listing, err := p.Next(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "did not advance") {
+		t.Fatalf("GetSnapshotDiffListingPaginator Next: got %v, %v, want error of not advancing", listing, err)
+	}
+	if len(*requests) != 2 {
+		t.Errorf("GetSnapshotDiffListingPaginator requests: got %d, want 2", len(*requests))
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PermissionSticky is the sticky bit of a permission, with which only the owner of a file, the owner of its directory,
+// and the superuser can delete or rename the file in the directory, such as 1777 of /tmp.
+const PermissionSticky Permission = 01000
+
+// ParsePermission parses a permission in octal, such as 755 or 1777,
+// in the symbolic syntax of ls, such as rwxr-x--x, rwxrwxrwt or drwxr-xr-x with the type of file ignored,
+// or in the symbolic syntax of chmod applied to no permission, such as u=rwx,g=rx, see Permission.Chmod.
+func ParsePermission(s string) (Permission, error) {
+	if p, ok := parseLsPermission(s); ok {
+		return p, nil
+	}
+	return Permission(0).Chmod(s)
+}
+
+// Chmod returns the permission p changed by mode as chmod of hadoop fs does,
+// mode is in octal, such as 755 or 1777, or in the symbolic syntax of [ugoa...][+-=][rwxXt...][,...],
+// such as u+w,g-x or a=r,u+w, where an empty who is the same as a,
+// X is x only if p has an execute bit set already, and t is the sticky bit, whatever who is.
+// See FileStatusProperties.Chmod for X of a directory.
+func (p Permission) Chmod(mode string) (Permission, error) {
+	return p.chmod(mode, false)
+}
+
+// Chmod returns the permission of the file changed by mode as chmod of hadoop fs does, see Permission.Chmod,
+// but X is x of a directory too, whether an execute bit is set or not.
+func (fi *FileStatusProperties) Chmod(mode string) (Permission, error) {
+	return fi.Permission.chmod(mode, fi.IsDir())
+}
+
+// chmod returns the permission p changed by mode, of a directory if dir.
+func (p Permission) chmod(mode string, dir bool) (Permission, error) {
+	mode = strings.TrimSpace(mode)
+	if mode != "" && strings.Trim(mode, "01234567") == "" {
+		i, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || i > 01777 {
+			return 0, fmt.Errorf("invalid permission %q", mode)
+		}
+		return Permission(i), nil
+	}
+	if mode == "" {
+		return 0, fmt.Errorf("invalid permission %q", mode)
+	}
+	for _, clause := range strings.Split(mode, ",") {
+		var err error
+		if p, err = p.chmodClause(strings.TrimSpace(clause), dir); err != nil {
+			return 0, fmt.Errorf("invalid permission %q: %w", mode, err)
+		}
+	}
+	return p, nil
+}
+
+// chmodClause returns the permission p changed by a clause of the symbolic mode, such as ug+rw-x, of a directory if dir.
+func (p Permission) chmodClause(clause string, dir bool) (Permission, error) {
+	var shifts []uint
+	i := 0
+	for ; i < len(clause) && strings.IndexByte("ugoa", clause[i]) >= 0; i++ {
+		switch clause[i] {
+		case 'u':
+			shifts = append(shifts, 6)
+		case 'g':
+			shifts = append(shifts, 3)
+		case 'o':
+			shifts = append(shifts, 0)
+		case 'a':
+			shifts = append(shifts, 6, 3, 0)
+		}
+	}
+	if len(shifts) == 0 {
+		shifts = []uint{6, 3, 0}
+	}
+	if i == len(clause) {
+		return 0, fmt.Errorf("missing operator in %q", clause)
+	}
+	for i < len(clause) {
+		op := clause[i]
+		if strings.IndexByte("+-=", op) < 0 {
+			return 0, fmt.Errorf("unknown operator %q in %q", op, clause)
+		}
+		i++
+		var action FsAction
+		var sticky bool
+		exeOk := dir || p&0111 != 0
+		for ; i < len(clause) && strings.IndexByte("+-=", clause[i]) < 0; i++ {
+			switch clause[i] {
+			case 'r':
+				action |= FsActionRead
+			case 'w':
+				action |= FsActionWrite
+			case 'x':
+				action |= FsActionExecute
+			case 'X':
+				if exeOk {
+					action |= FsActionExecute
+				}
+			case 't':
+				sticky = true
+			default:
+				return 0, fmt.Errorf("unknown permission %q in %q", clause[i], clause)
+			}
+		}
+		for _, shift := range shifts {
+			bits := Permission(action) << shift
+			switch op {
+			case '+':
+				p |= bits
+			case '-':
+				p &^= bits
+			case '=':
+				p = p&^(Permission(FsActionAll)<<shift) | bits
+			}
+		}
+		if sticky {
+			if op == '-' {
+				p &^= PermissionSticky
+			} else {
+				p |= PermissionSticky
+			}
+		}
+	}
+	return p, nil
+}
+
+// parseLsPermission parses a permission in the symbolic syntax of ls, such as rwxr-x--x,
+// with the type of file ahead optional, such as drwxr-xr-x, and the sticky bit as t or T in place of x of others.
+func parseLsPermission(s string) (Permission, bool) {
+	if len(s) == 10 && strings.IndexByte("-dl", s[0]) >= 0 {
+		s = s[1:]
+	}
+	if len(s) != 9 {
+		return 0, false
+	}
+	var p Permission
+	if c := s[8]; c == 't' || c == 'T' {
+		p |= PermissionSticky
+		if c == 't' {
+			s = s[:8] + "x"
+		} else {
+			s = s[:8] + "-"
+		}
+	}
+	for i, shift := range []uint{6, 3, 0} {
+		a, err := ParseFsAction(s[i*3 : i*3+3])
+		if err != nil {
+			return 0, false
+		}
+		p |= Permission(a) << shift
+	}
+	return p, true
+}
+
+// Symbolic returns the permission in the symbolic syntax of ls, such as rwxr-x--x,
+// with the sticky bit as t in place of x of others, or T if others cannot execute, such as rwxrwxrwt.
+func (p Permission) Symbolic() string {
+	s := p.User().String() + p.Group().String() + p.Other().String()
+	if p.Sticky() {
+		if p.Other().Implies(FsActionExecute) {
+			s = s[:8] + "t"
+		} else {
+			s = s[:8] + "T"
+		}
+	}
+	return s
+}
+
+// User returns the actions permitted to the owner.
+func (p Permission) User() FsAction {
+	return FsAction(p>>6) & FsActionAll
+}
+
+// Group returns the actions permitted to the group.
+func (p Permission) Group() FsAction {
+	return FsAction(p>>3) & FsActionAll
+}
+
+// Other returns the actions permitted to the others.
+func (p Permission) Other() FsAction {
+	return FsAction(p) & FsActionAll
+}
+
+// Sticky reports whether the sticky bit is set.
+func (p Permission) Sticky() bool {
+	return p&PermissionSticky != 0
+}
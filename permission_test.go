@@ -0,0 +1,165 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import "testing"
+
+func TestParsePermission(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Permission
+	}{
+		{"755", 0755},
+		{"0644", 0644},
+		{"1777", 01777},
+		{"0", 0},
+		{"rwxr-x--x", 0751},
+		{"drwxr-xr-x", 0755},
+		{"-rw-r--r--", 0644},
+		{"rwxrwxrwt", 01777},
+		{"rwxrwxrwT", 01776},
+		{"u=rwx,g=rx", 0750},
+		{"a=r,u+w", 0644},
+		{"=rw", 0666},
+		{"+t", 01000},
+	}
+	for _, tt := range tests {
+		got, err := ParsePermission(tt.s)
+		if err != nil {
+			t.Errorf("ParsePermission(%q) failed: %s", tt.s, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePermission(%q): got %o, want %o", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPermission_Chmod(t *testing.T) {
+	tests := []struct {
+		p    Permission
+		mode string
+		want Permission
+	}{
+		{0644, "755", 0755},
+		{0644, "1777", 01777},
+		{0777, "u+x,g-w,o=", 0750},
+		{0640, "u+x,g-w,o=", 0740},
+		{0644, "u+x, g-w, o=", 0740},
+		{0644, "a=rX", 0444},
+		{0744, "a=rX", 0555},
+		{0600, "ug+rw-x", 0660},
+		{0755, "+t", 01755},
+		{01755, "-t", 0755},
+		{01755, "o=rx", 01755},
+		{0755, "go-rx+t", 01700},
+		{0, "a+rwx", 0777},
+	}
+	for _, tt := range tests {
+		got, err := tt.p.Chmod(tt.mode)
+		if err != nil {
+			t.Errorf("%o Chmod(%q) failed: %s", tt.p, tt.mode, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%o Chmod(%q): got %o, want %o", tt.p, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestFileStatusProperties_Chmod(t *testing.T) {
+	tests := []struct {
+		fi   FileStatusProperties
+		mode string
+		want Permission
+	}{
+		{FileStatusProperties{Type: FileTypeFile, Permission: 0644}, "a=rX", 0444},
+		{FileStatusProperties{Type: FileTypeDirectory, Permission: 0644}, "a=rX", 0555},
+		{FileStatusProperties{Type: FileTypeFile, Permission: 0600}, "go+X", 0600},
+		{FileStatusProperties{Type: FileTypeDirectory, Permission: 0600}, "go+X", 0611},
+		{FileStatusProperties{Type: FileTypeDirectory, Permission: 0644}, "u+x,g-w,o=", 0740},
+	}
+	for _, tt := range tests {
+		got, err := tt.fi.Chmod(tt.mode)
+		if err != nil {
+			t.Errorf("%s %o Chmod(%q) failed: %s", tt.fi.Type, tt.fi.Permission, tt.mode, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s %o Chmod(%q): got %o, want %o", tt.fi.Type, tt.fi.Permission, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestPermission_Chmod_Invalid(t *testing.T) {
+	for _, mode := range []string{"", " ", "u+q", "0o2000", "2000", "8", "u", "ug", "u*x", "u+x,", "x+u", "rwxr-x--"} {
+		if got, err := Permission(0644).Chmod(mode); err == nil {
+			t.Errorf("Chmod(%q): got %o, want error", mode, got)
+		}
+		if got, err := ParsePermission(mode); err == nil {
+			t.Errorf("ParsePermission(%q): got %o, want error", mode, got)
+		}
+	}
+}
+
+func TestParseLsPermission(t *testing.T) {
+	tests := []struct {
+		s        string
+		want     Permission
+		symbolic string
+	}{
+		{"-rwxr-xr-t", 01755, "rwxr-xr-t"},
+		{"drwxrwxrwt", 01777, "rwxrwxrwt"},
+		{"rw-r--r-T", 01644, "rw-r--r-T"},
+		{"lrwxrwxrwx", 0777, "rwxrwxrwx"},
+		{"---------", 0, "---------"},
+		{"r-x-w---x", 0521, "r-x-w---x"},
+	}
+	for _, tt := range tests {
+		got, ok := parseLsPermission(tt.s)
+		if !ok {
+			t.Errorf("parseLsPermission(%q) failed", tt.s)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseLsPermission(%q): got %o, want %o", tt.s, got, tt.want)
+		}
+		if got.Symbolic() != tt.symbolic {
+			t.Errorf("%o Symbolic(): got %q, want %q", got, got.Symbolic(), tt.symbolic)
+		}
+		if p, ok := parseLsPermission(got.Symbolic()); !ok || p != got {
+			t.Errorf("parseLsPermission(%q): got %o, want %o", got.Symbolic(), p, got)
+		}
+	}
+
+	for _, s := range []string{"", "rwx", "rwxr-xr-", "xrwxr-xr-x", "rwxr-xr-xx", "rwtr-xr-x", "rwxr-xr-s", "r-wr-xr-x"} {
+		if got, ok := parseLsPermission(s); ok {
+			t.Errorf("parseLsPermission(%q): got %o, want failure", s, got)
+		}
+	}
+}
+
+func TestPermission_Symbolic(t *testing.T) {
+	tests := []struct {
+		p    Permission
+		want string
+	}{
+		{0755, "rwxr-xr-x"},
+		{0640, "rw-r-----"},
+		{01777, "rwxrwxrwt"},
+		{01776, "rwxrwxrwT"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Symbolic(); got != tt.want {
+			t.Errorf("%o Symbolic(): got %q, want %q", tt.p, got, tt.want)
+		}
+		if tt.p.User() != FsAction(tt.p>>6&7) || tt.p.Group() != FsAction(tt.p>>3&7) || tt.p.Other() != FsAction(tt.p&7) {
+			t.Errorf("%o User/Group/Other: got %s %s %s", tt.p, tt.p.User(), tt.p.Group(), tt.p.Other())
+		}
+		if tt.p.Sticky() != (tt.p&01000 != 0) {
+			t.Errorf("%o Sticky(): got %t", tt.p, tt.p.Sticky())
+		}
+	}
+}
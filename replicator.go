@@ -0,0 +1,351 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+// ReplicationResult summarizes a replication of a snapshot diff.
+type ReplicationResult struct {
+	Renamed int // number of files or directories renamed
+	Deleted int // number of files or directories deleted
+
+	// files of data copied, for the files and directories created or modified
+	CopyResult
+}
+
+// Replicator replicates a snapshottable directory incrementally from a cluster to another, as distcp -diff,
+// each cluster accessed by a client of its own.
+// The changes between two snapshots of the source, got by GETSNAPSHOTDIFF, are applied to the target,
+// which is expected to be the same as the source at the earlier snapshot, such as replicated to the last time.
+// Renames and deletions are applied by RENAME and DELETE on the target, without moving any data,
+// and only the files created or modified are copied, by ClusterCopier.
+//
+//go:generate go-option -type "Replicator"
+type Replicator struct {
+	src, dst *Client
+
+	// options
+	copierOpts []ClusterCopierOption
+}
+
+// NewReplicator returns a Replicator replicating from the cluster of src to the cluster of dst.
+func NewReplicator(src, dst *Client, opts ...ReplicatorOption) *Replicator {
+	r := &Replicator{src: src, dst: dst}
+	r.ApplyOptions(opts...)
+	return r
+}
+
+// ReplicateSnapshotDiff replicates the changes of the directory src between the snapshots from and to
+// to the directory dstPath of the cluster of dst, see Replicator.Replicate.
+func (c *Client) ReplicateSnapshotDiff(ctx context.Context, dst *Client, src, from, to, dstPath string, opts ...ReplicatorOption) (*ReplicationResult, error) {
+	return NewReplicator(c, dst, opts...).Replicate(ctx, src, from, to, dstPath)
+}
+
+// renameOp is a rename of the diff, moved by way of a temporary path.
+type renameOp struct {
+	source, target string // relative to the snapshot root
+	tmp            string // temporary path on the target
+}
+
+// Replicate applies the changes of the snapshottable directory src between its snapshots from and to
+// to the directory dst, and creates the snapshot to on dst at last, so that it is ready for the next replication.
+//
+// As DistCpSync of Hadoop, the files and directories renamed are moved into a temporary directory of dst
+// along with the deletions, the deepest first, then moved to their targets, the shallowest first,
+// so that a rename never depends on another one applied later. Then the data of the files created or modified
+// is copied from the snapshot to of src, where the paths under a directory renamed are translated into the new ones.
+//
+// The snapshot is not created if any file failed to be copied, and the error returned is of the first one.
+func (r *Replicator) Replicate(ctx context.Context, src, from, to, dst string) (*ReplicationResult, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	diff, err := r.snapshotDiff(ctx, src, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var renames []*renameOp
+	var deletes []string
+	var changes []DiffReportEntry
+	for _, entry := range diff.DiffList {
+		switch entry.Type {
+		case DiffReportEntryTypeRename:
+			renames = append(renames, &renameOp{source: path.Clean(entry.SourcePath), target: path.Clean(entry.TargetPath)})
+		case DiffReportEntryTypeDelete:
+			deletes = append(deletes, path.Clean(entry.SourcePath))
+		case DiffReportEntryTypeCreate, DiffReportEntryTypeModify:
+			changes = append(changes, entry)
+		}
+	}
+
+	result := &ReplicationResult{}
+	if err := r.applyRenamesAndDeletes(ctx, dst, renames, deletes, result); err != nil {
+		return result, err
+	}
+	if err := r.copyChanges(ctx, path.Join(src, ".snapshot", to), dst, changes, renames, result); err != nil {
+		return result, err
+	}
+	if result.Failed > 0 {
+		return result, fmt.Errorf("replicate %s: %d files failed to be copied, first: %w", src, result.Failed, result.Errors[0])
+	}
+
+	resp, err := r.dst.CreateSnapshotWithContext(ctx, &CreateSnapshotRequest{
+		ProxyUser:    r.dst.ProxyUser(),
+		Path:         types.Pointer(dst),
+		Snapshotname: types.Pointer(to),
+	})
+	if err != nil {
+		return result, fmt.Errorf("create snapshot %s of %s: %w", to, dst, err)
+	}
+	resp.Body.Close()
+	return result, nil
+}
+
+// snapshotDiff returns the diff of the directory dir between the snapshots from and to.
+func (r *Replicator) snapshotDiff(ctx context.Context, dir, from, to string) (*SnapshotDiffReport, error) {
+	resp, err := r.src.GetSnapshotDiffWithContext(ctx, &GetSnapshotDiffRequest{
+		ProxyUser:       r.src.ProxyUser(),
+		Path:            types.Pointer(dir),
+		Oldsnapshotname: types.Pointer(from),
+		Snapshotname:    types.Pointer(to),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return &resp.SnapshotDiffReport, nil
+}
+
+// applyRenamesAndDeletes moves the sources of renames into a temporary directory of dst and deletes the deletions,
+// in the order of the depth of the source, the deepest first, then moves the renamed to their targets,
+// the shallowest first.
+func (r *Replicator) applyRenamesAndDeletes(ctx context.Context, dst string, renames []*renameOp, deletes []string, result *ReplicationResult) error {
+	if len(renames) == 0 && len(deletes) == 0 {
+		return nil
+	}
+	type op struct {
+		source string
+		rename *renameOp // nil for deletion
+	}
+	var ops []op
+	for _, rename := range renames {
+		ops = append(ops, op{source: rename.source, rename: rename})
+	}
+	for _, name := range deletes {
+		ops = append(ops, op{source: name})
+	}
+	sort.SliceStable(ops, func(i, j int) bool {
+		return pathDepth(ops[i].source) > pathDepth(ops[j].source)
+	})
+
+	tmpDir := path.Join(dst, ".replicate."+strconv.FormatInt(time.Now().UnixNano(), 36)+".tmp")
+	if err := r.dst.mkdirAll(ctx, tmpDir); err != nil {
+		return &fs.PathError{Op: "mkdir", Path: tmpDir, Err: err}
+	}
+	for i, op := range ops {
+		name := path.Join(dst, op.source)
+		if op.rename == nil {
+			deleted, err := r.dst.deleteFile(ctx, name, true)
+			if err != nil {
+				return &fs.PathError{Op: "delete", Path: name, Err: err}
+			}
+			if deleted {
+				result.Deleted++
+			}
+			continue
+		}
+		op.rename.tmp = path.Join(tmpDir, strconv.Itoa(i))
+		if err := r.dst.renameFile(ctx, name, op.rename.tmp); err != nil {
+			return &fs.PathError{Op: "rename", Path: name, Err: err}
+		}
+	}
+
+	sorted := append([]*renameOp(nil), renames...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return pathDepth(sorted[i].target) < pathDepth(sorted[j].target)
+	})
+	for _, rename := range sorted {
+		target := path.Join(dst, rename.target)
+		if err := r.dst.mkdirAll(ctx, path.Dir(target)); err != nil {
+			return &fs.PathError{Op: "mkdir", Path: path.Dir(target), Err: err}
+		}
+		if err := r.dst.renameFile(ctx, rename.tmp, target); err != nil {
+			return &fs.PathError{Op: "rename", Path: rename.tmp, Err: err}
+		}
+		result.Renamed++
+	}
+
+	if _, err := r.dst.deleteFile(ctx, tmpDir, true); err != nil {
+		return &fs.PathError{Op: "delete", Path: tmpDir, Err: err}
+	}
+	return nil
+}
+
+// copyChanges copies the files and directories created or modified from the snapshot directory snapshot to dst.
+// The data of a directory modified is never copied, as its files changed are listed in the diff themselves.
+func (r *Replicator) copyChanges(ctx context.Context, snapshot, dst string, changes []DiffReportEntry, renames []*renameOp, result *ReplicationResult) error {
+	cc := NewClusterCopier(r.src, r.dst, r.copierOpts...)
+	var dirs, files []copyJob
+	for _, entry := range changes {
+		name := translateRenamed(entry, renames)
+		src := path.Join(snapshot, name)
+		info, err := r.src.fileStatus(ctx, src)
+		if err != nil {
+			return &fs.PathError{Op: "stat", Path: src, Err: err}
+		}
+		job := copyJob{
+			src:   src,
+			dst:   path.Join(dst, name),
+			perm:  info.Permission,
+			atime: info.AccessTime,
+			mtime: info.ModificationTime,
+			info:  info,
+		}
+		switch {
+		case info.Type == FileTypeSymlink:
+			continue
+		case info.IsDir() && entry.Type == DiffReportEntryTypeCreate:
+			copied, err := cc.Copy(ctx, job.src, job.dst)
+			if err != nil {
+				return err
+			}
+			result.add(copied)
+		case info.IsDir():
+			if err := cc.preserveAttrs(ctx, job); err != nil {
+				result.Errors = append(result.Errors, &fs.PathError{Op: "preserve", Path: job.src, Err: err})
+			}
+			dirs = append(dirs, job)
+		default:
+			files = append(files, job)
+		}
+	}
+	runCopyJobs(ctx, cc.concurrency, files, &result.CopyResult, cc.copy)
+
+	// times of directories are changed by the files copied into, the deepest first
+	if cc.preserve&PreserveTimes != 0 {
+		sort.SliceStable(dirs, func(i, j int) bool {
+			return pathDepth(dirs[i].dst) > pathDepth(dirs[j].dst)
+		})
+		for _, dir := range dirs {
+			if err := cc.dst.setFileTimes(ctx, dir.dst, accessTime(dir.atime), &dir.mtime); err != nil {
+				result.Errors = append(result.Errors, &fs.PathError{Op: "chtimes", Path: dir.src, Err: err})
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// add sums up other into the result.
+func (r *CopyResult) add(other *CopyResult) {
+	r.Copied += other.Copied
+	r.Skipped += other.Skipped
+	r.Failed += other.Failed
+	r.Bytes += other.Bytes
+	r.Errors = append(r.Errors, other.Errors...)
+}
+
+// translateRenamed returns the path of the entry created or modified, relative to the snapshot root,
+// with its ancestor renamed replaced by the target, as paths under a directory renamed are reported by the old one.
+// A path modified and renamed both is of the same file, while a path created and renamed both is of another one.
+func translateRenamed(entry DiffReportEntry, renames []*renameOp) string {
+	name := path.Clean(entry.SourcePath)
+	for _, rename := range renames {
+		if name == rename.source {
+			if entry.Type == DiffReportEntryTypeModify {
+				return rename.target
+			}
+			continue
+		}
+		if rename.source == "." || strings.HasPrefix(name, rename.source+"/") {
+			return path.Join(rename.target, strings.TrimPrefix(name, rename.source))
+		}
+	}
+	return name
+}
+
+// pathDepth returns the number of components of name relative to the snapshot root, 0 for the root.
+func pathDepth(name string) int {
+	if name == "." || name == "" {
+		return 0
+	}
+	return strings.Count(name, "/") + 1
+}
+
+// mkdirAll creates the directory path along with any necessary parents.
+func (c *Client) mkdirAll(ctx context.Context, path string) error {
+	resp, err := c.MkdirsWithContext(ctx, &MkdirsRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if !resp.Boolean {
+		return fs.ErrExist
+	}
+	return nil
+}
+
+// renameFile renames the file or directory src to dst, which must not exist.
+// An error wrapping fs.ErrExist is returned if dst exists, checked ahead, as RENAME moves src into dst if a directory,
+// and an error wrapping fs.ErrNotExist if src or the parent of dst does not exist.
+func (c *Client) renameFile(ctx context.Context, src, dst string) error {
+	if _, err := c.fileStatus(ctx, dst); err == nil {
+		return fmt.Errorf("rename to %s: %w", dst, fs.ErrExist)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	resp, err := c.RenameWithContext(ctx, &RenameRequest{
+		ProxyUser:   c.ProxyUser(),
+		Path:        types.Pointer(src),
+		Destination: types.Pointer(dst),
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if !resp.Boolean {
+		// RENAME answers false, instead of an error, if src or the parent of dst does not exist, or dst exists
+		if _, err := c.fileStatus(ctx, src); err != nil {
+			return err
+		}
+		if _, err := c.fileStatus(ctx, dst); err == nil {
+			return fmt.Errorf("rename to %s: %w", dst, fs.ErrExist)
+		}
+		if _, err := c.fileStatus(ctx, path.Dir(dst)); err != nil {
+			return fmt.Errorf("rename to %s: %w", dst, err)
+		}
+		return fmt.Errorf("rename %s to %s: refused by the namenode", src, dst)
+	}
+	return nil
+}
+
+// deleteFile deletes the file or directory path, and reports whether it existed.
+func (c *Client) deleteFile(ctx context.Context, path string, recursive bool) (bool, error) {
+	resp, err := c.DeleteWithContext(ctx, &DeleteRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+		Recursive: types.Pointer(recursive),
+	})
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return resp.Boolean, nil
+}
@@ -0,0 +1,12 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+// WithReplicatorCopierOptions sets the options of the ClusterCopier copying the files created or modified.
+func WithReplicatorCopierOptions(opts ...ClusterCopierOption) ReplicatorOption {
+	return ReplicatorOptionFunc(func(r *Replicator) {
+		r.copierOpts = append(r.copierOpts, opts...)
+	})
+}
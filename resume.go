@@ -0,0 +1,251 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+// UploadCheckpoint records the progress of a resumable upload, saved as JSON in a local file.
+type UploadCheckpoint struct {
+	Path        string `json:"path"`        // HDFS path of the target file
+	Committed   int64  `json:"committed"`   // bytes committed to the target file
+	Fingerprint string `json:"fingerprint"` // fingerprint of the local file
+}
+
+// LoadUploadCheckpoint reads the checkpoint saved in the local file name.
+func LoadUploadCheckpoint(name string) (*UploadCheckpoint, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var cp UploadCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parse checkpoint %s: %w", name, err)
+	}
+	return &cp, nil
+}
+
+// Save writes the checkpoint to the local file name, replacing it atomically.
+func (cp *UploadCheckpoint) Save(name string) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(name, data)
+}
+
+// ResumeUpload uploads the local file localPath to the file path, recording the progress in the local file checkpoint.
+// The content is uploaded in chunks as by Writer, and the checkpoint is saved after every chunk committed.
+// If a checkpoint of the same target and local file is found, the upload is resumed:
+// the target is truncated to the bytes committed, if longer because of a chunk partially written,
+// and the rest is sent by APPEND. Otherwise the target is created from scratch,
+// overwritten if it is left by an upload of a stale checkpoint.
+// The checkpoint is removed once the upload completes.
+// It returns the length of the target file.
+func (c *Client) ResumeUpload(ctx context.Context, localPath, path, checkpoint string, opts ...WriterOption) (int64, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	f, err := os.Open(localPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	cp := &UploadCheckpoint{Path: path, Fingerprint: fileFingerprint(fi)}
+	w := c.NewWriter(ctx, path, opts...)
+	if old, err := LoadUploadCheckpoint(checkpoint); err == nil && old.Path == cp.Path && old.Fingerprint == cp.Fingerprint {
+		committed, err := c.resumeUploadAt(ctx, path, old.Committed)
+		if err != nil {
+			return 0, err
+		}
+		if committed > 0 {
+			cp.Committed = committed
+			w.created = true
+			w.written = committed
+		} else {
+			w.overwrite = true
+		}
+	} else if err == nil {
+		// the target left by a stale checkpoint is of no use
+		w.overwrite = w.overwrite || old.Path == cp.Path
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+	if err := cp.Save(checkpoint); err != nil {
+		return 0, err
+	}
+	w.onFlush = func(written int64) error {
+		cp.Committed = written
+		return cp.Save(checkpoint)
+	}
+
+	if _, err := f.Seek(cp.Committed, io.SeekStart); err != nil {
+		return w.Written(), err
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		w.Close()
+		return w.Written(), err
+	}
+	if err := w.Close(); err != nil {
+		return w.Written(), err
+	}
+	return w.Written(), os.Remove(checkpoint)
+}
+
+// resumeUploadAt returns the bytes of the file path to resume an upload from, given committed recorded.
+// A tail written after committed is truncated, 0 is returned if the file is missing or shorter than committed.
+func (c *Client) resumeUploadAt(ctx context.Context, path string, committed int64) (int64, error) {
+	resp, err := c.GetFileStatusWithContext(ctx, &GetFileStatusRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+	})
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	resp.Body.Close()
+	length := resp.FileStatus.Length
+	if length < committed {
+		return 0, nil
+	}
+	if length > committed {
+		// A false result tells the last block is being recovered in the background,
+		// APPEND fails with RecoveryInProgressException until it is done, which is tried again by Writer.
+		resp, err := c.TruncateWithContext(ctx, &TruncateRequest{
+			ProxyUser: c.ProxyUser(),
+			Path:      types.Pointer(path),
+			NewLength: types.Pointer(committed),
+		})
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+	}
+	return committed, nil
+}
+
+// ResumeDownload downloads the file path to the local file localPath, resuming into the content already there.
+// A partial local file is continued by OPEN at the offset of its length, and restarted if it is longer than the file.
+// A transfer failed transiently is tried again from the byte it stopped at.
+// Once downloaded, the local file is verified by computing its checksum locally, see ComputeFileChecksum,
+// and comparing it with the one got by GETFILECHECKSUM, a *ChecksumMismatchError is returned if they differ.
+// It returns the length of the local file.
+func (c *Client) ResumeDownload(ctx context.Context, path, localPath string) (int64, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	resp, err := c.GetFileStatusWithContext(ctx, &GetFileStatusRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+	})
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	status := resp.FileStatus
+	if status.IsDir() {
+		return 0, fmt.Errorf("%s is a directory", path)
+	}
+
+	f, err := os.OpenFile(localPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if offset > status.Length {
+		if err := f.Truncate(0); err != nil {
+			return 0, err
+		}
+		offset = 0
+	}
+
+	for attempt := 0; offset < status.Length; attempt++ {
+		if attempt > 0 {
+			if err := sleepWithContext(ctx, retryBackoff(attempt)); err != nil {
+				return offset, err
+			}
+		}
+		n, err := c.readPart(ctx, path, filePart{Offset: offset, Length: status.Length - offset}, f, nil)
+		offset += n
+		if err != nil && (!isRetryableError(err) || attempt >= DefaultDownloadMaxRetries) {
+			return offset, err
+		}
+	}
+
+	if err := c.verifyChecksum(ctx, path, status.BlockSize, f); err != nil {
+		return offset, err
+	}
+	return offset, nil
+}
+
+// verifyChecksum compares the checksum of the file path with the one of the content of r.
+func (c *Client) verifyChecksum(ctx context.Context, path string, blockSize int64, r io.ReadSeeker) error {
+	expected, err := c.fileChecksum(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	want, err := expected.Decode()
+	if err != nil {
+		return err
+	}
+	got, err := ComputeFileChecksum(r, want.CombineMode, want.CrcType, blockSize, want.BytesPerCRC)
+	if err != nil {
+		return err
+	}
+	if actual := got.Encode(); !equalChecksum(expected, actual) {
+		return &ChecksumMismatchError{Path: path, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// fileFingerprint returns a fingerprint of the local file, changed if the file is modified.
+func fileFingerprint(fi os.FileInfo) string {
+	return fmt.Sprintf("%d-%d", fi.Size(), fi.ModTime().UnixNano())
+}
+
+// writeFileAtomic writes data to the local file name by renaming a temporary file written in the same directory.
+func writeFileAtomic(name string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), name)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
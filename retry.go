@@ -0,0 +1,76 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryBackoff    = time.Second
+	defaultMaxRetryBackoff = 10 * time.Second
+)
+
+// isRetryableError reports whether a failed request may succeed if tried again.
+// Transport errors are retryable, such as a connection reset or a response cut short,
+// so are the namenode exceptions telling to come back later, such as a lease of the file not recovered yet.
+// Any other error is permanent, such as an invalid request, or a failure to write what is read locally.
+func isRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var except *RemoteException
+	if errors.As(err, &except) {
+		switch except.JavaClassName {
+		case JavaClassNameAlreadyBeingCreatedException,
+			JavaClassNameRecoveryInProgressException,
+			JavaClassNameRetriableException,
+			JavaClassNameStandbyException:
+			return true
+		default:
+			return false
+		}
+	}
+	// syscall.Errno is a net.Error too, so a bare one is of a local file, not of the connection
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if _, local := netErr.(syscall.Errno); !local {
+			return true
+		}
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// retryBackoff returns how long to wait before the attempt-th retry, attempt starts from 1.
+func retryBackoff(attempt int) time.Duration {
+	backoff := defaultRetryBackoff
+	for i := 1; i < attempt && backoff < defaultMaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > defaultMaxRetryBackoff {
+		backoff = defaultMaxRetryBackoff
+	}
+	return backoff
+}
+
+// sleepWithContext pauses the current goroutine for at least the duration d, or until ctx is done.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	remote := func(javaClassName string) error {
+		return &RemoteException{Exception: javaClassName[strings.LastIndex(javaClassName, ".")+1:], JavaClassName: javaClassName}
+	}
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, false},
+		{io.ErrUnexpectedEOF, true},
+		{fmt.Errorf("read body: %w", io.ErrUnexpectedEOF), true},
+		{&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+		{&net.DNSError{Err: "no such host", Name: "namenode"}, true},
+		{fmt.Errorf("write: %w", syscall.EPIPE), true},
+		{remote(JavaClassNameAlreadyBeingCreatedException), true},
+		{remote(JavaClassNameRecoveryInProgressException), true},
+		{remote(JavaClassNameRetriableException), true},
+		{remote(JavaClassNameStandbyException), true},
+		{remote(JavaClassNameFileNotFoundException), false},
+		{remote(JavaClassNameAccessControlException), false},
+		{&fs.PathError{Op: "write", Path: "/tmp/file", Err: syscall.ENOSPC}, false},
+		{io.ErrShortWrite, false},
+		{errors.New("unexpected http status code"), false},
+	}
+	for i, tt := range tests {
+		if got := isRetryableError(tt.err); got != tt.want {
+			t.Errorf("#%d: isRetryableError(%v) = %t, want %t", i, tt.err, got, tt.want)
+		}
+	}
+}
+
+// failingWriterAt fails every write, as a local file on a full disk.
+type failingWriterAt struct{ err error }
+
+func (w failingWriterAt) WriteAt(p []byte, off int64) (int, error) { return 0, w.err }
+
+func TestDownloader_LocalWriteErrorNotRetried(t *testing.T) {
+	const data = "hello, webhdfs"
+	var opens int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch op := r.URL.Query().Get("op"); op {
+		case OpGetFileStatus:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"FileStatus":{"length":%d,"type":"FILE","blockSize":134217728,"replication":1,"permission":"644"}}`, len(data))
+		case OpOpen:
+			atomic.AddInt32(&opens, 1)
+			w.Header().Set("Content-Type", "application/octet-stream")
+			io.WriteString(w, data)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"RemoteException":{"exception":"IllegalArgumentException","javaClassName":"java.lang.IllegalArgumentException","message":"unexpected op %s"}}`, op)
+		}
+	}))
+	defer srv.Close()
+	c, err := New(strings.TrimPrefix(srv.URL, "http://"), WithDisableSSL(true), WithKerberosConfig(nil))
+	if err != nil {
+		t.Fatalf("create client %s", err)
+	}
+
+	writeErr := &fs.PathError{Op: "write", Path: "/tmp/file", Err: syscall.ENOSPC}
+	start := time.Now()
+	_, err = c.Download(context.Background(), "/file", failingWriterAt{writeErr})
+	if !errors.Is(err, syscall.ENOSPC) {
+		t.Errorf("Download() error = %v, want %v", err, writeErr)
+	}
+	if n := atomic.LoadInt32(&opens); n != 1 {
+		t.Errorf("OPEN sent %d times, want 1", n)
+	}
+	if elapsed := time.Since(start); elapsed >= defaultRetryBackoff {
+		t.Errorf("Download() returned in %s, want no backoff", elapsed)
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+// TrashCurrent is the name of the directory of a trash root, where files are moved into until checkpointed.
+const TrashCurrent = "Current"
+
+const (
+	trashCheckpointLayout    = "060102150405" // yyMMddHHmmss, the checkpoint of TrashPolicyDefault
+	trashOldCheckpointLayout = "0601021504"   // yyMMddHHmm, the checkpoint of Hadoop 1.x
+	trashMaxCheckpointTries  = 1000
+)
+
+// trashPermission is the permission of the directories created in trash.
+const trashPermission = 0700
+
+// ErrAlreadyInTrash is returned by MoveToTrash if the file is in trash already,
+// to be deleted permanently instead, as rm of hadoop fs does.
+var ErrAlreadyInTrash = errors.New("already in trash")
+
+// TrashCheckpoint is a checkpoint of trash, the directory the current trash is renamed to at a time.
+type TrashCheckpoint struct {
+	Path string    // path of the checkpoint directory
+	Time time.Time // time the checkpoint is created, parsed from the name
+}
+
+// Trash is a trash root of HDFS, managed with the layout of TrashPolicyDefault of Hadoop:
+// files deleted are moved into <trashroot>/Current/<original path>, and Current is renamed
+// to a checkpoint named by the time, such as <trashroot>/yyMMddHHmmss, to be expunged once expired.
+type Trash struct {
+	c    *Client
+	root string
+}
+
+// Trash returns the trash of the file or directory path, with the root got by GETTRASHROOT,
+// which is .Trash of the home directory of the user, or .Trash/<user> of the encryption zone containing path.
+func (c *Client) Trash(ctx context.Context, path string) (*Trash, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	resp, err := c.GetTrashRootWithContext(ctx, &GetTrashRootRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return &Trash{c: c, root: resp.Path}, nil
+}
+
+// MoveToTrash moves the file or directory path into the current trash of its trash root, see Trash.MoveToTrash.
+func (c *Client) MoveToTrash(ctx context.Context, path string) (string, error) {
+	t, err := c.Trash(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return t.MoveToTrash(ctx, path)
+}
+
+// Root returns the trash root.
+func (t *Trash) Root() string {
+	return t.root
+}
+
+// Current returns the directory files are moved into, <trashroot>/Current.
+func (t *Trash) Current() string {
+	return path.Join(t.root, TrashCurrent)
+}
+
+// MoveToTrash moves the file or directory name to <trashroot>/Current/<name>, and returns the path in trash.
+// name must be under the same trash root, that is not of another encryption zone.
+//
+// As TrashPolicyDefault of Hadoop, if a file in trash has the same path already,
+// the current time in milliseconds is appended to the name, such as Current/dir/file1600000000000,
+// and if a file is in the way of the parent directory, the same goes for the file,
+// such as Current/dir1600000000000/file, so that nothing in trash is ever replaced.
+//
+// An error wrapping ErrAlreadyInTrash is returned if name is in trash already.
+func (t *Trash) MoveToTrash(ctx context.Context, name string) (string, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	name = path.Join("/", name)
+	if name == t.root || strings.HasPrefix(name, t.root+"/") {
+		return "", &fs.PathError{Op: "trash", Path: name, Err: ErrAlreadyInTrash}
+	}
+	if name == "/" || strings.HasPrefix(t.root, name+"/") {
+		return "", &fs.PathError{Op: "trash", Path: name, Err: fmt.Errorf("cannot move %s to trash, as it contains the trash", name)}
+	}
+
+	trashPath := t.Current() + name
+	base := path.Dir(trashPath)
+	if err := t.mkdirAll(ctx, base); err != nil {
+		if !errors.Is(err, fs.ErrExist) {
+			return "", &fs.PathError{Op: "mkdir", Path: base, Err: err}
+		}
+		// a file is in the way, moved to trash before a directory of the same path
+		existing, err := t.existingAncestor(ctx, base)
+		if err != nil {
+			return "", err
+		}
+		base = existing + strconv.FormatInt(time.Now().UnixMilli(), 10) + strings.TrimPrefix(base, existing)
+		trashPath = path.Join(base, path.Base(trashPath))
+		if err := t.mkdirAll(ctx, base); err != nil {
+			return "", &fs.PathError{Op: "mkdir", Path: base, Err: err}
+		}
+	}
+
+	orig := trashPath
+	for {
+		exists, err := t.exists(ctx, trashPath)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			break
+		}
+		trashPath = orig + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	}
+	if err := t.c.renameFile(ctx, name, trashPath); err != nil {
+		return "", &fs.PathError{Op: "trash", Path: name, Err: err}
+	}
+	return trashPath, nil
+}
+
+// Restore moves the file or directory name in trash, under Current or a checkpoint,
+// back to its original path, and returns the path restored to.
+// The parent directories of the original path are created if missing.
+// The time appended to a name by MoveToTrash on collision is kept, as it cannot be told from the name itself.
+func (t *Trash) Restore(ctx context.Context, name string) (string, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	name = path.Clean(name)
+	rest := strings.TrimPrefix(name, t.root+"/")
+	i := strings.Index(rest, "/")
+	if rest == name || i < 0 {
+		return "", &fs.PathError{Op: "restore", Path: name, Err: fmt.Errorf("not a file in trash %s", t.root)}
+	}
+	orig := rest[i:]
+	if err := t.c.mkdirAll(ctx, path.Dir(orig)); err != nil {
+		return "", &fs.PathError{Op: "mkdir", Path: path.Dir(orig), Err: err}
+	}
+	if err := t.c.renameFile(ctx, name, orig); err != nil {
+		return "", &fs.PathError{Op: "restore", Path: name, Err: err}
+	}
+	return orig, nil
+}
+
+// Checkpoint renames Current to a checkpoint named by the current time, yyMMddHHmmss in local time,
+// suffixed by -1, -2 and so on if taken already, and returns the path of the checkpoint.
+// An empty path is returned if Current does not exist.
+func (t *Trash) Checkpoint(ctx context.Context) (string, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	current := t.Current()
+	exists, err := t.exists(ctx, current)
+	if err != nil || !exists {
+		return "", err
+	}
+
+	base := path.Join(t.root, time.Now().Format(trashCheckpointLayout))
+	checkpoint := base
+	for attempt := 1; ; attempt++ {
+		err := t.c.renameFile(ctx, current, checkpoint)
+		if err == nil {
+			return checkpoint, nil
+		}
+		if !errors.Is(err, fs.ErrExist) || attempt > trashMaxCheckpointTries {
+			return "", &fs.PathError{Op: "checkpoint", Path: current, Err: err}
+		}
+		checkpoint = base + "-" + strconv.Itoa(attempt)
+	}
+}
+
+// Checkpoints returns the checkpoints of the trash, sorted by name.
+// Directories not named as a checkpoint are ignored, as TrashPolicyDefault of Hadoop does.
+func (t *Trash) Checkpoints(ctx context.Context) ([]TrashCheckpoint, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	statuses, err := t.c.ListAll(ctx, t.root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var checkpoints []TrashCheckpoint
+	for _, st := range statuses {
+		if !st.IsDir() || st.PathSuffix == TrashCurrent {
+			continue
+		}
+		if tm, ok := trashCheckpointTime(st.PathSuffix); ok {
+			checkpoints = append(checkpoints, TrashCheckpoint{Path: path.Join(t.root, st.PathSuffix), Time: tm})
+		}
+	}
+	return checkpoints, nil
+}
+
+// Expunge deletes the checkpoints older than retention permanently, all of them if retention is 0,
+// and returns the paths of the checkpoints deleted. Current is left as it is, see Checkpoint.
+func (t *Trash) Expunge(ctx context.Context, retention time.Duration) ([]string, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	checkpoints, err := t.Checkpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var deleted []string
+	for _, checkpoint := range checkpoints {
+		if retention > 0 && now.Sub(checkpoint.Time) <= retention {
+			continue
+		}
+		if _, err := t.c.deleteFile(ctx, checkpoint.Path, true); err != nil {
+			return deleted, &fs.PathError{Op: "expunge", Path: checkpoint.Path, Err: err}
+		}
+		deleted = append(deleted, checkpoint.Path)
+	}
+	return deleted, nil
+}
+
+// mkdirAll creates the directory dir in trash along with any necessary parents, with trashPermission.
+func (t *Trash) mkdirAll(ctx context.Context, dir string) error {
+	resp, err := t.c.MkdirsWithContext(ctx, &MkdirsRequest{
+		ProxyUser:  t.c.ProxyUser(),
+		Path:       types.Pointer(dir),
+		Permission: types.Pointer(trashPermission),
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if !resp.Boolean {
+		return fs.ErrExist
+	}
+	return nil
+}
+
+// exists reports whether the file name exists.
+func (t *Trash) exists(ctx context.Context, name string) (bool, error) {
+	_, err := t.c.fileStatus(ctx, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// existingAncestor returns the nearest of name and its ancestors which exists.
+func (t *Trash) existingAncestor(ctx context.Context, name string) (string, error) {
+	for {
+		exists, err := t.exists(ctx, name)
+		if err != nil || exists || name == "/" {
+			return name, err
+		}
+		name = path.Dir(name)
+	}
+}
+
+// trashCheckpointTime returns the time of the checkpoint named name,
+// which is yyMMddHHmmss with an optional suffix of -N, or yyMMddHHmm of Hadoop 1.x.
+func trashCheckpointTime(name string) (time.Time, bool) {
+	for _, layout := range []string{trashCheckpointLayout, trashOldCheckpointLayout} {
+		if len(name) < len(layout) {
+			continue
+		}
+		suffix := name[len(layout):]
+		if suffix != "" {
+			if n, err := strconv.Atoi(strings.TrimPrefix(suffix, "-")); err != nil || n <= 0 || suffix[0] != '-' {
+				continue
+			}
+		}
+		if tm, err := time.ParseInLocation(layout, name[:len(layout)], time.Local); err == nil {
+			return tm, true
+		}
+	}
+	return time.Time{}, false
+}
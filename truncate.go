@@ -0,0 +1,65 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+// DefaultTruncateWaitTimeout is how long TruncateAndWait waits for the recovery of the last block at most.
+const DefaultTruncateWaitTimeout = 5 * time.Minute
+
+// TruncateAndWait truncates the file path to newLength, and waits until the file is usable again,
+// so that a following APPEND does not fail with AlreadyBeingCreatedException or RecoveryInProgressException.
+//
+// If newLength is not on a block boundary, TRUNCATE returns false and the last block is recovered in the background,
+// during which the file stays under construction, with a length other than newLength,
+// such as the one ending at the last block not truncated yet, more than newLength.
+// GETFILESTATUS is polled with backoff until the length is newLength, either less or more before,
+// which the namenode sets together with closing the file once the recovery is committed,
+// as FileStatus tells no under construction.
+//
+// It stops waiting once ctx is done, or DefaultTruncateWaitTimeout has passed, whichever comes first,
+// so that a recovery never committed does not block it forever.
+func (c *Client) TruncateAndWait(ctx context.Context, path string, newLength int64) error {
+	if ctx == nil {
+		panic("nil context")
+	}
+	resp, err := c.TruncateWithContext(ctx, &TruncateRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+		NewLength: types.Pointer(newLength),
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.Boolean {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTruncateWaitTimeout)
+	defer cancel()
+	for attempt := 1; ; attempt++ {
+		if err := sleepWithContext(ctx, retryBackoff(attempt)); err != nil {
+			return &fs.PathError{Op: "truncate", Path: path, Err: fmt.Errorf("wait for recovery of the last block: %w", err)}
+		}
+		info, err := c.fileStatus(ctx, path)
+		if err != nil {
+			if isRetryableError(err) {
+				continue
+			}
+			return err
+		}
+		if info.Length == newLength {
+			return nil
+		}
+	}
+}
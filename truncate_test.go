@@ -0,0 +1,141 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/searKing/golang/go/exp/types"
+
+	"github.com/searKing/webhdfs"
+)
+
+// truncateServer is a namenode of a single file, recovering the last block truncated
+// for the first recoveryPolls GETFILESTATUS, during which APPEND fails with RecoveryInProgressException,
+// and the file ends at the last block not truncated yet, or at the blocks before if shorter.
+type truncateServer struct {
+	mu            sync.Mutex
+	data          string
+	blockSize     int
+	recoveryPolls int // -1 for a recovery never committed
+	shorter       bool
+	recovering    bool
+	length        int // length ending at the last block not truncated yet
+	polls         int
+}
+
+func (s *truncateServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch op := r.URL.Query().Get("op"); op {
+	case webhdfs.OpTruncate:
+		var newLength int
+		fmt.Sscan(r.URL.Query().Get("newlength"), &newLength)
+		s.length = len(s.data)
+		if end := (newLength/s.blockSize + 1) * s.blockSize; end < s.length {
+			s.length = end
+		}
+		s.data = s.data[:newLength]
+		s.recovering = newLength%s.blockSize != 0
+		fmt.Fprintf(w, `{"boolean":%t}`, !s.recovering)
+	case webhdfs.OpGetFileStatus:
+		length := len(s.data)
+		if s.recovering {
+			s.polls++
+			if s.recoveryPolls >= 0 && s.polls > s.recoveryPolls {
+				s.recovering = false
+			} else if s.shorter {
+				length -= length % s.blockSize
+			} else {
+				length = s.length
+			}
+		}
+		fmt.Fprintf(w, `{"FileStatus":{"length":%d,"type":"FILE","blockSize":%d,"replication":1,"permission":"644"}}`, length, s.blockSize)
+	case webhdfs.OpAppend:
+		if s.recovering {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"RemoteException":{"exception":"RecoveryInProgressException",`+
+				`"javaClassName":"org.apache.hadoop.hdfs.protocol.RecoveryInProgressException","message":"Failed to APPEND_FILE"}}`)
+			return
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		s.data += string(data)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"RemoteException":{"exception":"IllegalArgumentException","javaClassName":"java.lang.IllegalArgumentException","message":"unexpected op %s"}}`, op)
+	}
+}
+
+func newTruncateClient(t *testing.T, s *truncateServer) *webhdfs.Client {
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	c, err := webhdfs.New(strings.TrimPrefix(srv.URL, "http://"), webhdfs.WithDisableSSL(true), webhdfs.WithKerberosConfig(nil))
+	if err != nil {
+		t.Fatalf("create client %s", err)
+	}
+	return c
+}
+
+func TestClient_TruncateAndWait_NotOnBlockBoundary(t *testing.T) {
+	for _, shorter := range []bool{false, true} {
+		s := &truncateServer{data: "Hello World!", blockSize: 4, recoveryPolls: 1, shorter: shorter}
+		c := newTruncateClient(t, s)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		// the length is 8, or 4 if shorter, while the last block is recovered, 6 once committed
+		if err := c.TruncateAndWait(ctx, "/found.txt", 6); err != nil {
+			t.Fatalf("webhdfs TruncateAndWait failed: %s", err)
+		}
+		if s.polls != 2 {
+			t.Errorf("TruncateAndWait polled %d times, want 2", s.polls)
+		}
+		resp, err := c.AppendWithContext(ctx, &webhdfs.AppendRequest{
+			Path: types.Pointer("/found.txt"),
+			Body: strings.NewReader("Again!"),
+		})
+		if err != nil {
+			t.Fatalf("webhdfs Append failed: %s", err)
+		}
+		resp.Body.Close()
+		if s.data != "Hello Again!" {
+			t.Errorf("expected %q, got %q", "Hello Again!", s.data)
+		}
+	}
+}
+
+func TestClient_TruncateAndWait_OnBlockBoundary(t *testing.T) {
+	s := &truncateServer{data: "Hello World!", blockSize: 4, recoveryPolls: -1}
+	c := newTruncateClient(t, s)
+
+	if err := c.TruncateAndWait(context.Background(), "/found.txt", 8); err != nil {
+		t.Fatalf("webhdfs TruncateAndWait failed: %s", err)
+	}
+	if s.polls != 0 {
+		t.Errorf("TruncateAndWait polled %d times, want 0", s.polls)
+	}
+}
+
+func TestClient_TruncateAndWait_Timeout(t *testing.T) {
+	s := &truncateServer{data: "Hello World!", blockSize: 4, recoveryPolls: -1}
+	c := newTruncateClient(t, s)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := c.TruncateAndWait(ctx, "/found.txt", 6)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TruncateAndWait: got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
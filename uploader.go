@@ -0,0 +1,291 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+const (
+	DefaultUploadBlocksize   = 128 << 20 // 128MB, default of dfs.blocksize
+	DefaultUploadPartSize    = DefaultUploadBlocksize
+	DefaultUploadConcurrency = 5
+	DefaultUploadMaxRetries  = 3
+)
+
+// Uploader uploads a file in parts concurrently, like the multipart upload of S3.
+// Every part is written by CREATE to a hidden temporary file next to the target,
+// then all parts are assembled by CONCAT into the first one, which is renamed to the target at last.
+// Parts created are deleted if the upload fails.
+//
+// CONCAT requires all blocks of the sources but the last to be full and of the same size,
+// so the part size is rounded up to a multiple of the block size, and all parts are created with the block size.
+// Up to concurrency parts are buffered in memory at the same time, that is concurrency * part size,
+// no less than concurrency * block size, 5 * 128MB by default, lower the concurrency or the block size to use less.
+//
+//go:generate go-option -type "Uploader"
+type Uploader struct {
+	c *Client
+
+	// options
+	partSize    int64
+	concurrency int
+	maxRetries  int
+	overwrite   bool
+	permission  *Permission
+	blocksize   int64
+	progress    func(uploaded int64)
+}
+
+// NewUploader returns an Uploader uploading files by c.
+func NewUploader(c *Client, opts ...UploaderOption) *Uploader {
+	u := &Uploader{
+		c:           c,
+		partSize:    DefaultUploadPartSize,
+		concurrency: DefaultUploadConcurrency,
+		maxRetries:  DefaultUploadMaxRetries,
+		blocksize:   DefaultUploadBlocksize,
+	}
+	u.ApplyOptions(opts...)
+	if u.blocksize <= 0 {
+		u.blocksize = DefaultUploadBlocksize
+	}
+	if u.partSize <= 0 {
+		u.partSize = DefaultUploadPartSize
+	}
+	if rem := u.partSize % u.blocksize; rem != 0 {
+		u.partSize += u.blocksize - rem
+	}
+	if u.concurrency <= 0 {
+		u.concurrency = DefaultUploadConcurrency
+	}
+	return u
+}
+
+// Upload uploads the content of r to the file path, see Uploader.Upload.
+func (c *Client) Upload(ctx context.Context, path string, r io.Reader, opts ...UploaderOption) (int64, error) {
+	return NewUploader(c, opts...).Upload(ctx, path, r)
+}
+
+// uploadPart is a part of the content being uploaded.
+type uploadPart struct {
+	Index int
+	Path  string
+	Data  []byte
+}
+
+// Upload uploads the content of r to the file path, parts read from r are uploaded concurrently.
+// It returns the number of bytes uploaded and the first error encountered, if any.
+// As RENAME never replaces an existing file, an existing path is renamed aside just before the rename if overwrite is set,
+// restored if the parts fail to take its place, and deleted once replaced, the upload fails with fs.ErrExist otherwise.
+func (u *Uploader) Upload(ctx context.Context, path string, r io.Reader) (int64, error) {
+	if ctx == nil {
+		panic("nil context")
+	}
+	if !u.overwrite {
+		if _, err := u.c.fileStatus(ctx, path); err == nil {
+			return 0, &fs.PathError{Op: "upload", Path: path, Err: fs.ErrExist}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return 0, &fs.PathError{Op: "upload", Path: path, Err: err}
+		}
+	}
+
+	prefix := u.partPrefix(path)
+	parts, uploaded, err := u.upload(ctx, prefix, r)
+	if err == nil {
+		err = u.complete(ctx, path, parts)
+	}
+	if err != nil {
+		u.abort(parts)
+		return uploaded, &fs.PathError{Op: "upload", Path: path, Err: err}
+	}
+	return uploaded, nil
+}
+
+// partPrefix returns the prefix of the hidden temporary parts of path, unique per upload.
+func (u *Uploader) partPrefix(name string) string {
+	dir, file := path.Split(name)
+	return path.Join(dir, "."+file+"."+strconv.FormatInt(time.Now().UnixNano(), 36)+".part")
+}
+
+// upload reads r into parts and uploads them concurrently, returning the paths of the parts created in order.
+func (u *Uploader) upload(ctx context.Context, prefix string, r io.Reader) ([]string, int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var parts []string
+	var uploaded int64
+	var firstErr error
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		cancel()
+	}
+
+	// buffers of the parts, bounded by concurrency
+	bufs := make(chan []byte, u.concurrency)
+	for i := 0; i < u.concurrency; i++ {
+		bufs <- nil
+	}
+	partCh := make(chan uploadPart)
+	var wg sync.WaitGroup
+	for i := 0; i < u.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for part := range partCh {
+				err := u.uploadPart(ctx, part)
+				bufs <- part.Data[:0]
+				if err != nil {
+					setErr(fmt.Errorf("upload part %d: %w", part.Index, err))
+					continue
+				}
+				mu.Lock()
+				uploaded += int64(len(part.Data))
+				if u.progress != nil {
+					u.progress(uploaded)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+L:
+	for i := 0; ; i++ {
+		var buf []byte
+		select {
+		case buf = <-bufs:
+		case <-ctx.Done():
+			break L
+		}
+		if buf == nil {
+			buf = make([]byte, u.partSize)
+		}
+		n, err := io.ReadFull(r, buf[:u.partSize])
+		if err == io.EOF && i > 0 {
+			break
+		}
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			setErr(err)
+			break
+		}
+		part := uploadPart{Index: i, Path: fmt.Sprintf("%s%05d", prefix, i), Data: buf[:n]}
+		// recorded before sent, so that a part partially created is cleaned up too
+		mu.Lock()
+		parts = append(parts, part.Path)
+		mu.Unlock()
+		select {
+		case partCh <- part:
+		case <-ctx.Done():
+			break L
+		}
+		if int64(n) < u.partSize {
+			break
+		}
+	}
+	close(partCh)
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return parts, uploaded, firstErr
+}
+
+// uploadPart creates the part file with its data, tried again on transient failures.
+func (u *Uploader) uploadPart(ctx context.Context, part uploadPart) error {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := sleepWithContext(ctx, retryBackoff(attempt)); err != nil {
+				return err
+			}
+		}
+		err := u.createPart(ctx, part)
+		if err == nil {
+			return nil
+		}
+		if !isRetryableError(err) || attempt >= u.maxRetries {
+			return err
+		}
+	}
+}
+
+func (u *Uploader) createPart(ctx context.Context, part uploadPart) error {
+	resp, err := u.c.CreateWithContext(ctx, &CreateRequest{
+		ProxyUser:     u.c.ProxyUser(),
+		Path:          types.Pointer(part.Path),
+		Body:          bytes.NewReader(part.Data),
+		ContentLength: types.Pointer(int64(len(part.Data))),
+		Overwrite:     types.Pointer(true),
+		Blocksize:     types.Pointer(u.blocksize),
+		Permission:    u.createPermission(),
+	})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// complete concatenates all parts into the first one, and renames it to path,
+// replacing the existing file by way of a backup if overwrite, see replaceFile.
+func (u *Uploader) complete(ctx context.Context, path string, parts []string) error {
+	if len(parts) > 1 {
+		resp, err := u.c.ConcatWithContext(ctx, &ConcatRequest{
+			ProxyUser:   u.c.ProxyUser(),
+			Path:        types.Pointer(parts[0]),
+			SourcePaths: parts[1:],
+		})
+		if err != nil {
+			return fmt.Errorf("concat parts: %w", err)
+		}
+		resp.Body.Close()
+	}
+
+	rename := u.c.renameFile
+	if u.overwrite {
+		rename = u.c.replaceFile
+	}
+	if err := rename(ctx, parts[0], path); err != nil {
+		return fmt.Errorf("rename parts: %w", err)
+	}
+	return nil
+}
+
+// abort deletes the parts left, ignoring any error.
+// The parts are deleted even if ctx is done, as the upload may have failed for that.
+func (u *Uploader) abort(parts []string) {
+	for _, part := range parts {
+		resp, err := u.c.Delete(&DeleteRequest{
+			ProxyUser: u.c.ProxyUser(),
+			Path:      types.Pointer(part),
+			Recursive: types.Pointer(false),
+		})
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+func (u *Uploader) createPermission() *int {
+	if u.permission == nil {
+		return nil
+	}
+	return types.Pointer(int(*u.permission))
+}
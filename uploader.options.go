@@ -0,0 +1,55 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+// WithUploaderPartSize sets the size of a part, rounded up to a multiple of the block size,
+// up to concurrency parts of which are buffered in memory at the same time.
+func WithUploaderPartSize(partSize int64) UploaderOption {
+	return UploaderOptionFunc(func(u *Uploader) {
+		u.partSize = partSize
+	})
+}
+
+// WithUploaderConcurrency sets the max number of parts uploaded at the same time.
+func WithUploaderConcurrency(concurrency int) UploaderOption {
+	return UploaderOptionFunc(func(u *Uploader) {
+		u.concurrency = concurrency
+	})
+}
+
+// WithUploaderMaxRetries sets how many times a part failed transiently is tried again, 0 disables retry.
+func WithUploaderMaxRetries(maxRetries int) UploaderOption {
+	return UploaderOptionFunc(func(u *Uploader) {
+		u.maxRetries = maxRetries
+	})
+}
+
+// WithUploaderOverwrite sets whether an existing file is replaced.
+func WithUploaderOverwrite(overwrite bool) UploaderOption {
+	return UploaderOptionFunc(func(u *Uploader) {
+		u.overwrite = overwrite
+	})
+}
+
+// WithUploaderPermission sets the permission of the file created.
+func WithUploaderPermission(perm Permission) UploaderOption {
+	return UploaderOptionFunc(func(u *Uploader) {
+		u.permission = perm.New()
+	})
+}
+
+// WithUploaderBlocksize sets the block size of the file created.
+func WithUploaderBlocksize(blocksize int64) UploaderOption {
+	return UploaderOptionFunc(func(u *Uploader) {
+		u.blocksize = blocksize
+	})
+}
+
+// WithUploaderProgress sets a function called with the total bytes uploaded each time a part is done.
+func WithUploaderProgress(progress func(uploaded int64)) UploaderOption {
+	return UploaderOptionFunc(func(u *Uploader) {
+		u.progress = progress
+	})
+}
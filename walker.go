@@ -0,0 +1,172 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"syscall"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+// SkipDir is used as a return value from WalkFunc to indicate that
+// the directory named in the call is to be skipped, the same as fs.SkipDir.
+var SkipDir = fs.SkipDir
+
+// SkipAll is used as a return value from WalkFunc to indicate that
+// all remaining files and directories are to be skipped, like fs.SkipAll of Go 1.20.
+var SkipAll = errors.New("skip everything and stop the walk")
+
+// WalkFunc is the type of the function called by Walk to visit each file or directory,
+// with the semantics of fs.WalkDirFunc.
+//
+// The path argument contains the argument to Walk as a prefix.
+// The info argument is the status of the named path, with PathPrefix filled in as the directory listed,
+// which is the directory containing path unless walked into by a symlink.
+// If the walk fails to get the status of root, or to list a directory, fn is called with the error,
+// see fs.WalkDirFunc for how the error is handled.
+type WalkFunc func(path string, info *FileStatus, err error) error
+
+// Walker walks the file tree of HDFS, listing directories by LISTSTATUS_BATCH.
+//
+//go:generate go-option -type "Walker"
+type Walker struct {
+	c *Client
+
+	// options
+	followSymlinks bool
+}
+
+// NewWalker returns a Walker walking by c.
+func NewWalker(c *Client, opts ...WalkerOption) *Walker {
+	w := &Walker{c: c}
+	w.ApplyOptions(opts...)
+	return w
+}
+
+// Walk walks the file tree rooted at root, see Walker.Walk.
+func (c *Client) Walk(ctx context.Context, root string, fn WalkFunc, opts ...WalkerOption) error {
+	return NewWalker(c, opts...).Walk(ctx, root, fn)
+}
+
+// Walk walks the file tree rooted at root, calling fn for each file or directory in the tree, including root,
+// with the semantics of fs.WalkDir: fn may return SkipDir or SkipAll to skip a directory or the rest of the walk.
+//
+// The files of a directory are walked in lexical order, as listed batch by batch by the namenode,
+// so a directory is never loaded as a whole.
+// Symbolic links are not followed unless WithWalkerFollowSymlinks is set,
+// a link to an ancestor directory is then reported to fn with a *fs.PathError of syscall.ELOOP.
+func (w *Walker) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	if ctx == nil {
+		panic("nil context")
+	}
+	info, err := w.c.fileStatus(ctx, root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = w.walk(ctx, root, root, info, fn, map[int64]bool{})
+	}
+	if err == SkipDir || err == SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walk recursively descends name, calling fn.
+// realName is the path of name with symlinks followed resolved, which is listed in fact.
+// ancestors holds the file ids of the directories on the way from root, to detect symlink cycles.
+func (w *Walker) walk(ctx context.Context, name, realName string, info *FileStatus, fn WalkFunc, ancestors map[int64]bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := fn(name, info, nil); err != nil || !info.IsDir() {
+		if err == SkipDir && info.IsDir() {
+			// Successfully skipped directory.
+			err = nil
+		}
+		return err
+	}
+
+	ancestors[info.FileId] = true
+	defer delete(ancestors, info.FileId)
+
+	var startAfter *string
+	for {
+		statuses, more, err := w.c.listDirBatch(ctx, realName, startAfter)
+		if err != nil {
+			// Second call, to report ListStatusBatch error.
+			err = fn(name, info, err)
+			if err == SkipDir {
+				err = nil
+			}
+			return err
+		}
+
+		for i := range statuses {
+			child := &statuses[i]
+			childName := path.Join(name, child.PathSuffix)
+			childRealName := path.Join(realName, child.PathSuffix)
+			if w.followSymlinks && child.Type == FileTypeSymlink {
+				target, targetName, err := w.resolveSymlink(ctx, child)
+				if err == nil && target.IsDir() && ancestors[target.FileId] {
+					err = &fs.PathError{Op: "walk", Path: childName, Err: syscall.ELOOP}
+				}
+				if err != nil {
+					if err := fn(childName, child, err); err != nil {
+						if err == SkipDir {
+							return nil
+						}
+						return err
+					}
+					continue
+				}
+				child, childRealName = target, targetName
+			}
+			if err := w.walk(ctx, childName, childRealName, child, fn, ancestors); err != nil {
+				if err == SkipDir {
+					// SkipDir on a file skips the remaining files in the directory.
+					return nil
+				}
+				return err
+			}
+		}
+		if !more {
+			return nil
+		}
+		startAfter = types.Pointer(statuses[len(statuses)-1].PathSuffix)
+	}
+}
+
+// resolveSymlink returns the path and the status of the file the symlink link refers to.
+// The status is named as the link, with PathPrefix and PathSuffix of the link.
+func (w *Walker) resolveSymlink(ctx context.Context, link *FileStatus) (*FileStatus, string, error) {
+	target := link.Symlink
+	if !path.IsAbs(target) {
+		target = path.Join(link.PathPrefix, target)
+	}
+	info, err := w.c.fileStatus(ctx, target)
+	if err != nil {
+		return nil, "", err
+	}
+	info.PathPrefix = link.PathPrefix
+	info.PathSuffix = link.PathSuffix
+	return info, target, nil
+}
+
+// fileStatus returns the status of the file path.
+func (c *Client) fileStatus(ctx context.Context, path string) (*FileStatus, error) {
+	resp, err := c.GetFileStatusWithContext(ctx, &GetFileStatusRequest{
+		ProxyUser: c.ProxyUser(),
+		Path:      types.Pointer(path),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return &resp.FileStatus, nil
+}
@@ -0,0 +1,12 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+// WithWalkerFollowSymlinks sets whether symbolic links to directories are walked into.
+func WithWalkerFollowSymlinks(follow bool) WalkerOption {
+	return WalkerOptionFunc(func(w *Walker) {
+		w.followSymlinks = follow
+	})
+}
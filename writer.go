@@ -0,0 +1,244 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/searKing/golang/go/exp/types"
+)
+
+const (
+	DefaultWriterChunkSize  = 8 << 20 // 8MB
+	DefaultWriterMaxRetries = 10
+)
+
+var _ io.WriteCloser = (*Writer)(nil)
+
+// Writer uploads a file incrementally, without buffering the whole content in memory.
+// Data written is buffered in chunks, the first chunk is sent by CREATE and every chunk after by APPEND.
+// A chunk failed transiently is tried again from the length the namenode reports for the file,
+// so bytes accepted by a failed request are never sent twice.
+//
+//go:generate go-option -type "Writer"
+type Writer struct {
+	c    *Client
+	ctx  context.Context
+	path string
+
+	// options
+	chunkSize   int
+	maxRetries  int
+	overwrite   bool
+	permission  *Permission
+	blocksize   *int64
+	replication *int
+
+	buf     []byte
+	created bool  // file created by CREATE
+	written int64 // bytes committed to the file
+	closed  bool
+	err     error // sticky error
+
+	onFlush func(written int64) error // called after every chunk committed
+}
+
+// NewWriter returns a Writer uploading to the file path, the file is created on the first flush.
+func (c *Client) NewWriter(ctx context.Context, path string, opts ...WriterOption) *Writer {
+	if ctx == nil {
+		panic("nil context")
+	}
+	w := &Writer{
+		c:          c,
+		ctx:        ctx,
+		path:       path,
+		chunkSize:  DefaultWriterChunkSize,
+		maxRetries: DefaultWriterMaxRetries,
+	}
+	w.ApplyOptions(opts...)
+	if w.chunkSize <= 0 {
+		w.chunkSize = DefaultWriterChunkSize
+	}
+	return w
+}
+
+// Write buffers p, full chunks are flushed to the file.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, &fs.PathError{Op: "write", Path: w.path, Err: fs.ErrClosed}
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	var n int
+	for len(p) > 0 {
+		m := w.chunkSize - len(w.buf)
+		if m > len(p) {
+			m = len(p)
+		}
+		w.buf = append(w.buf, p[:m]...)
+		p = p[m:]
+		n += m
+		if len(w.buf) == w.chunkSize {
+			if err := w.Flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Flush sends any buffered data to the file, the file is created if not yet.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.buf) == 0 && w.created {
+		return nil
+	}
+	if err := w.flush(w.buf); err != nil {
+		w.err = &fs.PathError{Op: "write", Path: w.path, Err: err}
+		return w.err
+	}
+	w.buf = w.buf[:0]
+	if w.onFlush != nil {
+		if err := w.onFlush(w.written); err != nil {
+			w.err = &fs.PathError{Op: "write", Path: w.path, Err: err}
+			return w.err
+		}
+	}
+	return nil
+}
+
+// Close flushes the buffered data and closes the Writer.
+// An empty file is created if nothing has been written.
+// As Close returns an error only to implement io.Closer, the total bytes written to the file
+// must be got by Written after Close, or by Finish, which closes the Writer and returns them at once.
+func (w *Writer) Close() error {
+	if w.closed {
+		return &fs.PathError{Op: "close", Path: w.path, Err: fs.ErrClosed}
+	}
+	err := w.Flush()
+	w.closed = true
+	w.buf = nil
+	return err
+}
+
+// Finish closes the Writer as Close does, and returns the total bytes written to the file,
+// which are the bytes committed before the error if Close fails.
+func (w *Writer) Finish() (int64, error) {
+	err := w.Close()
+	return w.Written(), err
+}
+
+// Written returns the number of bytes committed to the file.
+func (w *Writer) Written() int64 {
+	return w.written
+}
+
+// flush sends chunk to the file, tried again on transient failures.
+func (w *Writer) flush(chunk []byte) error {
+	for attempt := 0; ; attempt++ {
+		// CREATE with overwrite can simply be sent again.
+		if attempt > 0 && (w.created || !w.overwrite) {
+			// The failed request may have been applied partially,
+			// resume from the length of the file on the namenode.
+			length, exist, err := w.length()
+			if err != nil {
+				if !isRetryableError(err) || attempt > w.maxRetries {
+					return err
+				}
+				if err := sleepWithContext(w.ctx, retryBackoff(attempt)); err != nil {
+					return err
+				}
+				continue
+			}
+			if exist {
+				committed := length - w.written
+				if committed < 0 || committed > int64(len(chunk)) {
+					return fmt.Errorf("file length %d changed unexpectedly, %d bytes written and %d bytes in flight",
+						length, w.written, len(chunk))
+				}
+				w.created = true
+				w.written += committed
+				chunk = chunk[committed:]
+				if len(chunk) == 0 {
+					return nil
+				}
+			}
+		}
+
+		err := w.send(chunk)
+		if err == nil {
+			w.created = true
+			w.written += int64(len(chunk))
+			return nil
+		}
+		if !isRetryableError(err) || attempt >= w.maxRetries {
+			return err
+		}
+		if err := sleepWithContext(w.ctx, retryBackoff(attempt+1)); err != nil {
+			return err
+		}
+	}
+}
+
+// send creates the file with chunk if not created yet, appends chunk otherwise.
+func (w *Writer) send(chunk []byte) error {
+	if !w.created {
+		resp, err := w.c.CreateWithContext(w.ctx, &CreateRequest{
+			ProxyUser:     w.c.ProxyUser(),
+			Path:          types.Pointer(w.path),
+			Body:          bytes.NewReader(chunk),
+			ContentLength: types.Pointer(int64(len(chunk))),
+			Overwrite:     types.Pointer(w.overwrite),
+			Blocksize:     w.blocksize,
+			Replication:   w.replication,
+			Permission:    w.createPermission(),
+		})
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	resp, err := w.c.AppendWithContext(w.ctx, &AppendRequest{
+		ProxyUser:     w.c.ProxyUser(),
+		Path:          types.Pointer(w.path),
+		Body:          bytes.NewReader(chunk),
+		ContentLength: types.Pointer(int64(len(chunk))),
+	})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// length returns the length of the file on the namenode.
+func (w *Writer) length() (length int64, exist bool, err error) {
+	resp, err := w.c.GetFileStatusWithContext(w.ctx, &GetFileStatusRequest{
+		ProxyUser: w.c.ProxyUser(),
+		Path:      types.Pointer(w.path),
+	})
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+	return resp.FileStatus.Length, true, nil
+}
+
+func (w *Writer) createPermission() *int {
+	if w.permission == nil {
+		return nil
+	}
+	return types.Pointer(int(*w.permission))
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 The searKing Author. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webhdfs
+
+// WithWriterChunkSize sets the size of data buffered before sent by a single CREATE or APPEND.
+func WithWriterChunkSize(chunkSize int) WriterOption {
+	return WriterOptionFunc(func(w *Writer) {
+		w.chunkSize = chunkSize
+	})
+}
+
+// WithWriterMaxRetries sets how many times a chunk failed transiently is tried again, 0 disables retry.
+func WithWriterMaxRetries(maxRetries int) WriterOption {
+	return WriterOptionFunc(func(w *Writer) {
+		w.maxRetries = maxRetries
+	})
+}
+
+// WithWriterOverwrite sets whether an existing file is overwritten by CREATE.
+func WithWriterOverwrite(overwrite bool) WriterOption {
+	return WriterOptionFunc(func(w *Writer) {
+		w.overwrite = overwrite
+	})
+}
+
+// WithWriterPermission sets the permission of the file created.
+func WithWriterPermission(perm Permission) WriterOption {
+	return WriterOptionFunc(func(w *Writer) {
+		w.permission = perm.New()
+	})
+}
+
+// WithWriterBlocksize sets the block size of the file created.
+func WithWriterBlocksize(blocksize int64) WriterOption {
+	return WriterOptionFunc(func(w *Writer) {
+		w.blocksize = &blocksize
+	})
+}
+
+// WithWriterReplication sets the number of replications of the file created.
+func WithWriterReplication(replication int) WriterOption {
+	return WriterOptionFunc(func(w *Writer) {
+		w.replication = &replication
+	})
+}